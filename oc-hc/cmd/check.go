@@ -5,24 +5,23 @@ Copyright © 2023 Givaldo Lins <gilins@redhat.com>
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"os/signal"
 	"path/filepath"
+	"syscall"
 
 	"github.com/fatih/color"
+	"github.com/givaldolins/openshift-cluster-health-check/oc-hc/pkg/checks"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 
 	"k8s.io/cli-runtime/pkg/genericclioptions"
-	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 )
 
-const (
-	affirmative = "True"
-	negative    = "False"
-)
-
 // Struct type for this command
 type checkOptions struct {
 	kubeconfig       string
@@ -55,19 +54,7 @@ func init() {
 // Function to run some verifications
 func complete(cmd *cobra.Command, args []string) checkOptions {
 	// Get kubeconfig flag
-	kube, err := cmd.Flags().GetString("kubeconfig")
-	if err != nil {
-		customPanic(err, true)
-	}
-	// Use default kubeconfig if not passed via flag
-	if kube == "" {
-		kube = filepath.Join(os.Getenv("HOME"), ".kube", "config")
-
-		fmt.Printf("%s Using default kubeconfig: %s\n", color.YellowString("[Info]"), kube)
-
-	} else {
-		fmt.Printf("%s Using informed kubeconfig: %s\n", color.YellowString("[Info]"), kube)
-	}
+	kube := getKubeconfig(cmd)
 
 	// Check if container-restart has been passed via flag
 	cr, err := cmd.Flags().GetInt32("container-restart")
@@ -100,96 +87,170 @@ func complete(cmd *cobra.Command, args []string) checkOptions {
 
 func run(obj checkOptions) {
 
-	var config *rest.Config
-
-	// Build a new clientConfig from flag kubeconfig and instantiate a new clientset
-	config, err := clientcmd.BuildConfigFromFlags("", obj.kubeconfig)
+	// Instantiate the clients used by the checks
+	clients, err := checks.NewClients(getRestConfig(obj.kubeconfig))
 	if err != nil {
-		fmt.Printf("%s kubeconfig invalid, tryin to use current-context\n", color.YellowString("[Info]"))
-
-		configFlags := genericclioptions.NewConfigFlags(false)
-		config, _ = configFlags.ToRESTConfig()
+		customPanic(err, obj.debug)
 	}
 
-	clientset, err := kubernetes.NewForConfig(config)
+	opts, err := checksOptions(obj)
 	if err != nil {
-		if obj.debug {
-			customPanic(err, true)
-		} else {
-			customPanic(err, false)
-		}
+		customPanic(err, obj.debug)
 	}
 
 	// control plane checks
-	err = coStatus(config)
-	if err != nil {
-		customError(err, obj.debug)
+	list := []checks.Check{
+		checks.CoStatus,
+		checks.APIStatus,
+		checks.APIServiceStatus,
+		checks.APIPerformanceStatus,
+		checks.EtcdStatus,
+		checks.EtcdPerformanceStatus,
+		checks.EtcdSecurityStatus,
+		checks.MachineConfigPoolStatus,
+		// nodes checks
+		checks.CSRStatus,
+		checks.NodeStatus,
 	}
 
-	err = apiStatus(clientset)
-	if err != nil {
-		customError(err, obj.debug)
+	// network checks
+	if obj.network {
+		list = append(list, checks.NetworkStatus)
 	}
 
-	err = etcdStatus(clientset)
-	if err != nil {
-		customError(err, obj.debug)
+	list = append(list,
+		// cluster wide checks
+		checks.NetworkConfigStatus,
+		checks.IngressStatus,
+		checks.RouteStatus,
+		checks.WebhookStatus,
+		checks.CapacityStatus,
+		checks.AlertsStatus,
+		checks.PromQLStatus,
+		checks.CustomStatus,
+		checks.VersionStatus,
+		checks.ClusterVersionStatus,
+		checks.APIRequestCountStatus,
+		checks.CertificateStatus,
+		checks.TLSProbeStatus,
+		// namespace related checks
+		checks.PodStatus,
+		checks.PDBStatus,
+		checks.ServiceStatus,
+		checks.EventStatus,
+		// external checks
+		checks.PluginStatus,
+	)
+
+	// Cancel the running check on Ctrl-C so it can clean up its resources
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	for _, check := range list {
+		result, checkErr := check(ctx, clients, opts)
+		printResult(result, checkErr, obj.debug)
+		if ctx.Err() != nil {
+			fmt.Printf("%s Interrupted, skipping the remaining checks\n", color.YellowString("[Info]"))
+			break
+		}
 	}
+}
 
-	err = machineConfigPoolStatus()
-	if err != nil {
-		customError(err, obj.debug)
+// Build the options for the checks from the flags and the config file
+func checksOptions(obj checkOptions) (checks.Options, error) {
+	opts := checks.Options{
+		ContainerRestart: obj.containerRestart,
+		AlertSource:      viper.GetString("alerts.source"),
 	}
 
-	// nodes checks
-	err = csrStatus(clientset)
+	err := viper.UnmarshalKey("promql.checks", &opts.PromQLChecks)
 	if err != nil {
-		customError(err, obj.debug)
+		return opts, err
 	}
 
-	err = nodeStatus(clientset)
+	err = viper.UnmarshalKey("custom.checks", &opts.CustomChecks)
 	if err != nil {
-		customError(err, obj.debug)
+		return opts, err
 	}
 
-	// network checks
-	if obj.network {
-		err = networkStatus()
-		if err != nil {
-			customError(err, obj.debug)
-		}
+	// Plugins in the plugins dir take precedence over the ones in the PATH
+	opts.Plugins.Dir, err = expandHome(viper.GetString("plugins.dir"))
+	if err != nil {
+		return opts, err
 	}
-
-	// cluster wide checks
-	err = capacityStatus(clientset, config)
+	opts.Plugins.Dirs = filepath.SplitList(os.Getenv("PATH"))
+	opts.Plugins.Timeout = viper.GetDuration("plugins.timeout")
+	opts.Plugins.Kubeconfig = obj.kubeconfig
+	opts.Plugins.Version = version
+	opts.Plugins.Debug = obj.debug
+
+	opts.Version.GraphURL = viper.GetString("version.graphURL")
+	opts.Version.GraphFile = viper.GetString("version.graphFile")
+	opts.Version.LifecycleFile = viper.GetString("version.lifecycleFile")
+	opts.Version.HistoryLimit = viper.GetInt("version.historyLimit")
+
+	opts.Certificates.AllNamespaces = viper.GetBool("certificates.allNamespaces")
+	opts.Certificates.WarningDays = viper.GetInt("certificates.warningDays")
+	opts.Certificates.CriticalDays = viper.GetInt("certificates.criticalDays")
+	err = opts.Certificates.Validate()
 	if err != nil {
-		customError(err, obj.debug)
+		return opts, err
 	}
 
-	err = alertsStatus(config)
+	opts.Etcd.Window = viper.GetDuration("etcd.window")
+	opts.Etcd.BackupCronJob = viper.GetString("etcd.backup.cronJob")
+	opts.Etcd.BackupNamespace = viper.GetString("etcd.backup.namespace")
+	opts.Etcd.BackupMaxAge = viper.GetDuration("etcd.backup.maxAge")
+
+	opts.Ingress.TestRoute = viper.GetBool("ingress.testRoute")
+
+	opts.Network.Image = viper.GetString("network.image")
+	opts.Network.PullSecret = viper.GetString("network.pullSecret")
+	opts.Network.NodeSelector = viper.GetStringMapString("network.nodeSelector")
+	opts.Network.Timeout = viper.GetDuration("network.timeout")
+	opts.Network.ProbeEach = viper.GetString("network.probeEach")
+	opts.Network.Matrix = viper.GetBool("network.matrix")
+	opts.Network.MatrixMaxNodes = viper.GetInt("network.matrixMaxNodes")
+	err = viper.UnmarshalKey("network.tolerations", &opts.Network.Tolerations)
 	if err != nil {
-		customError(err, obj.debug)
+		return opts, err
 	}
-
-	err = versionStatus(config)
+	err = viper.UnmarshalKey("network.targets", &opts.Network.Targets)
 	if err != nil {
-		customError(err, obj.debug)
+		return opts, err
 	}
 
-	// namespace related checks
-	err = podStatus(clientset, obj.containerRestart)
+	return opts, nil
+}
+
+// Get the kubeconfig from the flags or use the default one
+func getKubeconfig(cmd *cobra.Command) string {
+	kube, err := cmd.Flags().GetString("kubeconfig")
 	if err != nil {
-		customError(err, obj.debug)
+		customPanic(err, true)
 	}
+	// Use default kubeconfig if not passed via flag
+	if kube == "" {
+		kube = filepath.Join(os.Getenv("HOME"), ".kube", "config")
 
-	err = pdbStatus(clientset)
-	if err != nil {
-		customError(err, obj.debug)
+		fmt.Printf("%s Using default kubeconfig: %s\n", color.YellowString("[Info]"), kube)
+
+	} else {
+		fmt.Printf("%s Using informed kubeconfig: %s\n", color.YellowString("[Info]"), kube)
 	}
 
-	err = eventStatus(clientset)
+	return kube
+}
+
+// Build a new clientConfig from the kubeconfig, or from the current-context when it is invalid
+func getRestConfig(kubeconfig string) *rest.Config {
+	config, err := clientcmd.BuildConfigFromFlags("", kubeconfig)
 	if err != nil {
-		customError(err, obj.debug)
+		fmt.Printf("%s kubeconfig invalid, tryin to use current-context\n", color.YellowString("[Info]"))
+
+		configFlags := genericclioptions.NewConfigFlags(false)
+		config, _ = configFlags.ToRESTConfig()
 	}
 
+	return config
 }
@@ -0,0 +1,79 @@
+/*
+Copyright © 2023 Givaldo Lins <gilins@redhat.com>
+*/
+package cmd
+
+import (
+	"context"
+	"os"
+
+	"github.com/givaldolins/openshift-cluster-health-check/oc-hc/pkg/checks"
+	"github.com/spf13/cobra"
+)
+
+// Struct type for this command
+type upgradeOptions struct {
+	kubeconfig string
+	target     string
+	debug      bool
+}
+
+// upgradeCmd represents the upgrade-readiness command
+var upgradeCmd = &cobra.Command{
+	Use:   "upgrade-readiness",
+	Short: "Check if an OpenShift cluster is ready to be upgraded to a target minor release",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		obj := completeUpgrade(cmd)
+		runUpgrade(obj)
+	},
+}
+
+// Function to define flags
+func init() {
+
+	rootCmd.AddCommand(upgradeCmd)
+	upgradeCmd.Flags().BoolP("debug", "d", false, "(default false) Print golang error messages")
+	upgradeCmd.Flags().StringP("kubeconfig", "k", "", "(optional) Path for the kubeconfig file to be used")
+	upgradeCmd.Flags().StringP("target", "t", "", "Target minor release, like 4.14")
+	cobra.CheckErr(upgradeCmd.MarkFlagRequired("target"))
+}
+
+// Function to run some verifications
+func completeUpgrade(cmd *cobra.Command) upgradeOptions {
+	kube := getKubeconfig(cmd)
+
+	target, err := cmd.Flags().GetString("target")
+	if err != nil {
+		customPanic(err, true)
+	}
+
+	debug, err := cmd.Flags().GetBool("debug")
+	if err != nil {
+		customPanic(err, true)
+	}
+
+	return upgradeOptions{
+		kubeconfig: kube,
+		target:     target,
+		debug:      debug,
+	}
+}
+
+func runUpgrade(obj upgradeOptions) {
+
+	// Instantiate the clients used by the checks
+	clients, err := checks.NewClients(getRestConfig(obj.kubeconfig))
+	if err != nil {
+		customPanic(err, obj.debug)
+	}
+
+	opts := checks.Options{Upgrade: checks.UpgradeOptions{Target: obj.target}}
+	result, err := checks.UpgradeReadiness(context.TODO(), clients, opts)
+	printResult(result, err, obj.debug)
+
+	// Exit with an error on a NO-GO or INCOMPLETE verdict so it can be used in pipelines
+	if err != nil || !result.Healthy() {
+		os.Exit(1)
+	}
+}
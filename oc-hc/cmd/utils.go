@@ -6,8 +6,12 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
 
 	"github.com/fatih/color"
+	"github.com/givaldolins/openshift-cluster-health-check/oc-hc/pkg/checks"
+	"github.com/rodaine/table"
 )
 
 func customError(err error, debug bool) {
@@ -25,3 +29,75 @@ func customPanic(err error, debug bool) {
 		os.Exit(1)
 	}
 }
+
+// Expand a leading ~ to the home directory of the current user
+func expandHome(path string) (string, error) {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, strings.TrimPrefix(path, "~")), nil
+}
+
+// Print the result of a check, nil results are checks with nothing to run
+func printResult(result *checks.Result, err error, debug bool) {
+	if result != nil {
+		fmt.Print(color.New(color.Bold).Sprintln(result.Title))
+		for _, section := range result.Sections {
+			printSection(section)
+		}
+	}
+	if err != nil {
+		customError(err, debug)
+	}
+}
+
+// Print a section of a check
+func printSection(section checks.Section) {
+	if section.Title != "" {
+		fmt.Printf(" - %s\n", section.Title)
+	}
+
+	switch section.Status {
+	case checks.StatusWarning:
+		fmt.Printf("  %s %s\n", color.RedString("[Warning]"), section.Message)
+	case checks.StatusError:
+		fmt.Printf("  %s %s\n", color.RedString("[Error]"), section.Message)
+	case checks.StatusSkipped:
+		fmt.Printf("  %s %s\n", color.YellowString("[Skipped]"), section.Message)
+	default:
+		fmt.Printf("  %s %s\n", color.YellowString("[Info]"), section.Message)
+	}
+
+	if section.Table != nil {
+		printTable(section.Table)
+	}
+	fmt.Println()
+}
+
+// Print the table of a section
+func printTable(t *checks.Table) {
+	header := make([]interface{}, len(t.Header))
+	for i, column := range t.Header {
+		header[i] = column
+	}
+	if len(header) > 0 {
+		header[0] = fmt.Sprintf("  %s", header[0])
+	}
+
+	tbl := table.New(header...).WithPadding(5)
+	for _, row := range t.Rows {
+		values := make([]interface{}, len(row))
+		for i, value := range row {
+			values[i] = value
+		}
+		if len(values) > 0 {
+			values[0] = fmt.Sprintf("  %s", values[0])
+		}
+		tbl.AddRow(values...)
+	}
+	tbl.Print()
+}
@@ -0,0 +1,244 @@
+/*
+Copyright © 2023 Givaldo Lins <gilins@redhat.com>
+*/
+package checks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+)
+
+// Sources that can be used to retrieve alerts
+const (
+	AlertSourceAlertmanager = "alertmanager"
+	AlertSourceThanos       = "thanos"
+)
+
+// Structs for alerts
+type AlertResponse struct {
+	Status string `json:"status"`
+	Data   []struct {
+		Labels *AlertLabels
+		Status *AlertStatus
+	} `json:"data"`
+}
+type AlertLabels struct {
+	Alertname string `json:"alertname"`
+	Namespace string `json:"namespace"`
+	Severity  string `json:"severity"`
+}
+type AlertStatus struct {
+	State string `json:"state"`
+}
+
+// Structs for the Thanos Querier alerts and rules endpoints
+type thanosAlertsResponse struct {
+	Status string `json:"status"`
+	Data   struct {
+		Alerts []struct {
+			Labels AlertLabels `json:"labels"`
+			State  string      `json:"state"`
+		} `json:"alerts"`
+	} `json:"data"`
+}
+type thanosRulesResponse struct {
+	Status string `json:"status"`
+	Data   struct {
+		Groups []struct {
+			Name  string `json:"name"`
+			Rules []struct {
+				Name      string `json:"name"`
+				Health    string `json:"health"`
+				LastError string `json:"lastError"`
+			} `json:"rules"`
+		} `json:"groups"`
+	} `json:"data"`
+}
+
+// AlertsStatus lists all current firing alerts
+func AlertsStatus(ctx context.Context, clients *Clients, opts Options) (*Result, error) {
+	result := &Result{Title: "Checking alerts..."}
+
+	// Get bearer token used by both sources
+	bearerToken, err := getBearerToken(clients.RestConfig)
+	if err != nil {
+		return result, err
+	}
+
+	// Use Alertmanager unless Thanos Querier has been selected
+	if opts.AlertSource == "" || opts.AlertSource == AlertSourceAlertmanager {
+		alertmanagerHost, routeErr := getRouteHost(ctx, clients, "openshift-monitoring", "alertmanager-main")
+		if routeErr == nil {
+			return result, alertmanagerAlerts(ctx, result, alertmanagerHost, bearerToken)
+		}
+		result.add(Section{Status: StatusInfo, Message: fmt.Sprintf("Unable to get the Alertmanager route, falling back to Thanos Querier: %s", routeErr)})
+	} else if opts.AlertSource != AlertSourceThanos {
+		return result, fmt.Errorf("invalid alerts source %q, valid sources are %q and %q", opts.AlertSource, AlertSourceAlertmanager, AlertSourceThanos)
+	}
+
+	host, err := getRouteHost(ctx, clients, "openshift-monitoring", "thanos-querier")
+	if err != nil {
+		return result, err
+	}
+
+	return result, thanosAlerts(ctx, result, host, bearerToken)
+}
+
+// List alerts in firing state from Alertmanager
+func alertmanagerAlerts(ctx context.Context, result *Result, host string, bearerToken string) error {
+	// Define URL for alerts endpoint
+	alertmanagerURL := "https://" + host + "/api/v1/alerts"
+
+	// Request all current alerts
+	var alerts AlertResponse
+	err := getJSON(ctx, alertmanagerURL, bearerToken, &alerts)
+	if err != nil {
+		return err
+	}
+
+	// Create a new table for the output
+	table := newTable("ALERTNAME", "NAMESPACE", "SEVERITY", "STATE")
+
+	// Set output
+	if len(alerts.Data) > 0 {
+		for _, value := range alerts.Data {
+			name := value.Labels.Alertname
+			state := value.Status.State
+			namespace := value.Labels.Namespace
+			severity := value.Labels.Severity
+			table.addRow(name, namespace, severity, state)
+		}
+		result.add(Section{Status: StatusWarning, Message: "Found Alerts in firing state", Table: table})
+	} else {
+		result.add(Section{Status: StatusInfo, Message: "There is no Alerts in AlertManager in firing state at this time"})
+	}
+
+	return nil
+}
+
+// List firing and pending alerts and rules in error state from Thanos Querier
+func thanosAlerts(ctx context.Context, result *Result, host string, bearerToken string) error {
+	// Request all current alerts
+	var alerts thanosAlertsResponse
+	err := getJSON(ctx, "https://"+host+"/api/v1/alerts", bearerToken, &alerts)
+	if err != nil {
+		return err
+	}
+
+	// Create a new table for the alerts
+	alertsTable := newTable("ALERTNAME", "NAMESPACE", "SEVERITY", "STATE")
+
+	// Set output
+	section := Section{Title: "Checking alerts from Thanos Querier..."}
+	if len(alerts.Data.Alerts) > 0 {
+		for _, alert := range alerts.Data.Alerts {
+			alertsTable.addRow(alert.Labels.Alertname, alert.Labels.Namespace, alert.Labels.Severity, alert.State)
+		}
+		section.Status = StatusWarning
+		section.Message = "Found Alerts in firing or pending state"
+		section.Table = alertsTable
+	} else {
+		section.Status = StatusInfo
+		section.Message = "There is no Alerts in firing or pending state at this time"
+	}
+	result.add(section)
+
+	// Request all rules
+	var rules thanosRulesResponse
+	err = getJSON(ctx, "https://"+host+"/api/v1/rules", bearerToken, &rules)
+	if err != nil {
+		return err
+	}
+
+	// Create a new table for the rules
+	rulesTable := newTable("RULE", "GROUP", "LAST ERROR")
+
+	// Check rules health
+	warning := false
+	for _, group := range rules.Data.Groups {
+		for _, rule := range group.Rules {
+			if rule.Health == "err" {
+				warning = true
+				rulesTable.addRow(rule.Name, group.Name, rule.LastError)
+			}
+		}
+	}
+
+	// Set output
+	section = Section{Title: "Checking alerting and recording rules..."}
+	if warning {
+		section.Status = StatusWarning
+		section.Message = "There is one or more rules in error state"
+		section.Table = rulesTable
+	} else {
+		section.Status = StatusInfo
+		section.Message = "There is no rule in error state"
+	}
+	result.add(section)
+
+	return nil
+}
+
+// Get the host of a route
+func getRouteHost(ctx context.Context, clients *Clients, namespace string, name string) (string, error) {
+	route, err := clients.Route.RouteV1().Routes(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return "", err
+	}
+
+	return route.Spec.Host, nil
+}
+
+// Get the user token from the kubeconfig
+func getBearerToken(config *rest.Config) (string, error) {
+	if config.BearerToken != "" {
+		return config.BearerToken, nil
+	}
+
+	if config.BearerTokenFile == "" {
+		return "", errors.New("the kubeconfig has no bearer token, log in with a token to query the monitoring stack")
+	}
+	token, err := os.ReadFile(config.BearerTokenFile)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(string(token)), nil
+}
+
+// Send an authenticated GET request and decode the json response
+func getJSON(ctx context.Context, url string, bearerToken string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", bearerToken))
+	req.Header.Add("Accept", "application/json")
+
+	client := &http.Client{}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	// Set body variable with server response
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("request to %s failed with status %s: %s", url, resp.Status, string(body))
+	}
+
+	return json.Unmarshal(body, out)
+}
@@ -0,0 +1,210 @@
+/*
+Copyright © 2023 Givaldo Lins <gilins@redhat.com>
+*/
+package checks
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// API server thresholds
+const (
+	// Window used by the API server queries
+	apiWindow = "5m"
+	// Request latency SLO for non-watch requests
+	maxAPILatency = 1.0
+	// Ratio of requests answered with 5xx or 429
+	maxAPIErrorRate = 0.01
+	// Inflight limits set by OpenShift on kube-apiserver and the usage reported as saturated
+	maxReadOnlyInflight = 3000
+	maxMutatingInflight = 1000
+	maxInflightUsage    = 0.8
+	// Number of clients listed by request volume
+	topClients = 10
+)
+
+// APIPerformanceStatus checks the API server latency, error rates and saturation from the in-cluster Prometheus
+func APIPerformanceStatus(ctx context.Context, clients *Clients, opts Options) (*Result, error) {
+	result := &Result{Title: "Checking API server performance..."}
+
+	// Get Thanos Querier route and user token
+	host, err := getRouteHost(ctx, clients, "openshift-monitoring", "thanos-querier")
+	if err != nil {
+		return result, err
+	}
+	bearerToken, err := getBearerToken(clients.RestConfig)
+	if err != nil {
+		return result, err
+	}
+
+	section, err := apiLatency(ctx, host, bearerToken)
+	if err != nil {
+		return result, err
+	}
+	result.add(section)
+
+	section, err = apiErrorRates(ctx, host, bearerToken)
+	if err != nil {
+		return result, err
+	}
+	result.add(section)
+
+	section, err = apiInflight(ctx, host, bearerToken)
+	if err != nil {
+		return result, err
+	}
+	result.add(section)
+
+	section, err = apiTopClients(ctx, clients)
+	if err != nil {
+		return result, err
+	}
+	result.add(section)
+
+	return result, nil
+}
+
+// Check the request latency p99 by verb for non-watch requests
+func apiLatency(ctx context.Context, host string, bearerToken string) (Section, error) {
+	section := Section{Title: "Checking request latency..."}
+
+	samples, err := queryPrometheus(ctx, host, bearerToken, `histogram_quantile(0.99, sum by (verb, le) (rate(apiserver_request_duration_seconds_bucket{job="apiserver",verb!~"WATCH|CONNECT"}[`+apiWindow+`])))`)
+	if err != nil {
+		return section, err
+	}
+
+	// Create a new table for the output
+	table := newTable("VERB", "P99", "THRESHOLD")
+
+	warning := false
+	for _, sample := range samples {
+		if math.IsNaN(sample.Value) {
+			continue
+		}
+		if sample.Value > maxAPILatency {
+			warning = true
+		}
+		table.addRow(sample.Labels["verb"], fmt.Sprintf("%.0fms", sample.Value*1000), fmt.Sprintf("%.0fms", maxAPILatency*1000))
+	}
+
+	// Set output
+	section.Status = warningStatus(warning)
+	section.Message = fmt.Sprintf("Request latency p99 is within %.0fs for all verbs over the last %s", maxAPILatency, apiWindow)
+	if warning {
+		section.Message = fmt.Sprintf("Request latency p99 is above %.0fs for one or more verbs over the last %s", maxAPILatency, apiWindow)
+	}
+	section.Table = table
+
+	return section, nil
+}
+
+// Check the ratio of requests answered with server errors or throttled
+func apiErrorRates(ctx context.Context, host string, bearerToken string) (Section, error) {
+	section := Section{Title: "Checking request error rates..."}
+
+	// Create a new table for the output
+	table := newTable("CODE", "RATE", "THRESHOLD")
+
+	warning := false
+	for _, code := range []string{"5..", "429"} {
+		query := fmt.Sprintf(`(sum(rate(apiserver_request_total{job="apiserver",code=~"%s"}[%s])) or vector(0)) / sum(rate(apiserver_request_total{job="apiserver"}[%s]))`, code, apiWindow, apiWindow)
+		samples, err := queryPrometheus(ctx, host, bearerToken, query)
+		if err != nil {
+			return section, err
+		}
+		for _, sample := range samples {
+			if math.IsNaN(sample.Value) {
+				continue
+			}
+			if sample.Value > maxAPIErrorRate {
+				warning = true
+			}
+			table.addRow(code, fmt.Sprintf("%.2f%%", sample.Value*100), fmt.Sprintf("%.2f%%", maxAPIErrorRate*100))
+		}
+	}
+
+	// Set output
+	section.Status = warningStatus(warning)
+	section.Message = fmt.Sprintf("Less than %.0f%% of the requests failed or were throttled over the last %s", maxAPIErrorRate*100, apiWindow)
+	if warning {
+		section.Message = fmt.Sprintf("More than %.0f%% of the requests failed or were throttled over the last %s", maxAPIErrorRate*100, apiWindow)
+	}
+	section.Table = table
+
+	return section, nil
+}
+
+// Check the inflight requests against the limits of every kube-apiserver
+func apiInflight(ctx context.Context, host string, bearerToken string) (Section, error) {
+	section := Section{Title: "Checking inflight requests..."}
+
+	samples, err := queryPrometheus(ctx, host, bearerToken, `max by (instance, request_kind) (max_over_time(apiserver_current_inflight_requests{job="apiserver"}[`+apiWindow+`]))`)
+	if err != nil {
+		return section, err
+	}
+
+	// Create a new table for the output
+	table := newTable("INSTANCE", "KIND", "INFLIGHT", "LIMIT", "USAGE")
+
+	warning := false
+	for _, sample := range samples {
+		limit := float64(maxReadOnlyInflight)
+		if sample.Labels["request_kind"] == "mutating" {
+			limit = maxMutatingInflight
+		}
+		usage := sample.Value / limit
+		if usage > maxInflightUsage {
+			warning = true
+		}
+		table.addRow(sample.Labels["instance"], sample.Labels["request_kind"], fmt.Sprintf("%.0f", sample.Value), fmt.Sprintf("%.0f", limit), fmt.Sprintf("%.0f%%", usage*100))
+	}
+
+	// Set output
+	section.Status = warningStatus(warning)
+	section.Message = fmt.Sprintf("Inflight requests peaked below %.0f%% of the limits over the last %s", maxInflightUsage*100, apiWindow)
+	if warning {
+		section.Message = fmt.Sprintf("Inflight requests peaked above %.0f%% of the limits over the last %s", maxInflightUsage*100, apiWindow)
+	}
+	section.Table = table
+
+	return section, nil
+}
+
+// List the clients with the highest request volume in the last 24h
+func apiTopClients(ctx context.Context, clients *Clients) (Section, error) {
+	section := Section{Title: "Checking top clients..."}
+
+	counts, err := clients.APIServer.ApiserverV1().APIRequestCounts().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return section, err
+	}
+
+	totals := map[string]int64{}
+	for _, count := range counts.Items {
+		users, _ := aggregateRequests(count)
+		for _, user := range users {
+			totals[user.name] += user.count
+		}
+	}
+	sorted := sortCounts(totals)
+	if len(sorted) > topClients {
+		sorted = sorted[:topClients]
+	}
+
+	// Create a new table for the output
+	table := newTable("USER", "REQUESTS (24H)")
+	for _, user := range sorted {
+		table.addRow(user.name, fmt.Sprint(user.count))
+	}
+
+	// Set output
+	section.Status = StatusInfo
+	section.Message = fmt.Sprintf("Top %d clients by request volume in the last 24h", len(sorted))
+	section.Table = table
+
+	return section, nil
+}
@@ -0,0 +1,149 @@
+/*
+Copyright © 2023 Givaldo Lins <gilins@redhat.com>
+*/
+package checks
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	apiserverv1 "github.com/openshift/api/apiserver/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Number of users listed for each deprecated API
+const topUsers = 3
+
+// Struct for a request count aggregated by user or verb
+type requestCount struct {
+	name  string
+	count int64
+}
+
+// APIRequestCountStatus checks for deprecated APIs requested in the last 24h
+func APIRequestCountStatus(ctx context.Context, clients *Clients, opts Options) (*Result, error) {
+	result := &Result{Title: "Checking deprecated APIs usage..."}
+
+	inUse, err := deprecatedAPIsInUse(ctx, clients)
+	if err != nil {
+		return result, err
+	}
+
+	// Removals closer in time first
+	sort.SliceStable(inUse, func(i, j int) bool {
+		return releaseBefore(inUse[i].Status.RemovedInRelease, inUse[j].Status.RemovedInRelease)
+	})
+
+	// Create a new table for the output
+	table := newTable("RESOURCE", "REMOVED IN", "REQUESTS (24H)", "TOP USERS", "VERBS")
+
+	for _, count := range inUse {
+		users, verbs := aggregateRequests(count)
+		if len(users) > topUsers {
+			users = users[:topUsers]
+		}
+		table.addRow(count.Name, count.Status.RemovedInRelease, fmt.Sprint(count.Status.RequestCount), formatCounts(users), formatCounts(verbs))
+	}
+
+	// Set output
+	if len(inUse) > 0 {
+		result.add(Section{Status: StatusWarning, Message: "There is one or more deprecated APIs requested in the last 24h that will be removed in a future release", Table: table})
+	} else {
+		result.add(Section{Status: StatusInfo, Message: "There is no deprecated API requested in the last 24h"})
+	}
+
+	return result, nil
+}
+
+// Compare two releases like 1.26 numerically, invalid ones go last
+func releaseBefore(a string, b string) bool {
+	aMajor, aMinor, aErr := parseVersion(a)
+	bMajor, bMinor, bErr := parseVersion(b)
+	switch {
+	case aErr != nil || bErr != nil:
+		return aErr == nil && bErr != nil
+	case aMajor != bMajor:
+		return aMajor < bMajor
+	}
+	return aMinor < bMinor
+}
+
+// Aggregate the requests of the last 24h by user and by verb, sorted by count
+func aggregateRequests(count apiserverv1.APIRequestCount) ([]requestCount, []requestCount) {
+	users := map[string]int64{}
+	verbs := map[string]int64{}
+	for _, hour := range count.Status.Last24h {
+		for _, node := range hour.ByNode {
+			for _, user := range node.ByUser {
+				users[user.UserName] += user.RequestCount
+				for _, verb := range user.ByVerb {
+					verbs[verb.Verb] += verb.RequestCount
+				}
+			}
+		}
+	}
+
+	return sortCounts(users), sortCounts(verbs)
+}
+
+// Sort counts from the highest to the lowest
+func sortCounts(counts map[string]int64) []requestCount {
+	sorted := []requestCount{}
+	for name, count := range counts {
+		sorted = append(sorted, requestCount{name: name, count: count})
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].count == sorted[j].count {
+			return sorted[i].name < sorted[j].name
+		}
+		return sorted[i].count > sorted[j].count
+	})
+
+	return sorted
+}
+
+// Format counts like name(count),name(count)
+func formatCounts(counts []requestCount) string {
+	formatted := []string{}
+	for _, c := range counts {
+		formatted = append(formatted, fmt.Sprintf("%s(%d)", c.name, c.count))
+	}
+	return strings.Join(formatted, ",")
+}
+
+// Get the deprecated APIs that have been requested in the last 24h
+func deprecatedAPIsInUse(ctx context.Context, clients *Clients) ([]apiserverv1.APIRequestCount, error) {
+	counts, err := clients.APIServer.ApiserverV1().APIRequestCounts().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	inUse := []apiserverv1.APIRequestCount{}
+	for _, count := range counts.Items {
+		if count.Status.RemovedInRelease != "" && count.Status.RequestCount > 0 {
+			inUse = append(inUse, count)
+		}
+	}
+
+	return inUse, nil
+}
+
+// Check if an API removed in the given kubernetes release is gone in the target one
+func removedBy(removedInRelease string, kubeMajor int, kubeMinor int) (bool, error) {
+	major, minor, err := parseVersion(removedInRelease)
+	if err != nil {
+		return false, fmt.Errorf("invalid removedInRelease: %w", err)
+	}
+
+	return major < kubeMajor || (major == kubeMajor && minor <= kubeMinor), nil
+}
+
+// Get the kubernetes release shipped with an OpenShift 4 minor release
+func kubeVersion(major int, minor int) (int, int) {
+	if major != 4 {
+		return 0, 0
+	}
+	return 1, minor + 13
+}
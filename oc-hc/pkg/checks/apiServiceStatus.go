@@ -0,0 +1,95 @@
+/*
+Copyright © 2023 Givaldo Lins <gilins@redhat.com>
+*/
+package checks
+
+import (
+	"context"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// GroupVersionResource for the aggregated APIServices
+var apiServiceResource = schema.GroupVersionResource{Group: "apiregistration.k8s.io", Version: "v1", Resource: "apiservices"}
+
+// APIService serving the metrics API used by the capacity check
+const metricsAPIService = "v1beta1.metrics.k8s.io"
+
+// APIServiceStatus checks for aggregated APIServices that are not available
+func APIServiceStatus(ctx context.Context, clients *Clients, opts Options) (*Result, error) {
+	result := &Result{Title: "Checking aggregated APIs..."}
+
+	apiservices, err := clients.Dynamic.Resource(apiServiceResource).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return result, err
+	}
+
+	// Create a new table for the output
+	table := newTable("APISERVICE", "SERVICE", "AVAILABLE", "REASON", "MESSAGE")
+
+	for _, apiservice := range apiservices.Items {
+		status, reason, message := apiServiceCondition(apiservice)
+		if status == affirmative {
+			continue
+		}
+		table.addRow(apiservice.GetName(), apiServiceBackend(apiservice), status, reason, truncate(message, 80))
+	}
+
+	// Set output
+	if len(table.Rows) > 0 {
+		result.add(Section{Status: StatusWarning, Message: "There is one or more aggregated APIs not available, discovery fails for clients using them", Table: table})
+	} else {
+		result.add(Section{Status: StatusInfo, Message: fmt.Sprintf("All %d APIServices are available", len(apiservices.Items))})
+	}
+
+	return result, nil
+}
+
+// Check if an APIService is available, it returns the reason when it is not
+func apiServiceAvailable(ctx context.Context, clients *Clients, name string) (bool, string, error) {
+	apiservice, err := clients.Dynamic.Resource(apiServiceResource).Get(ctx, name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return false, fmt.Sprintf("APIService %s not found", name), nil
+	}
+	if err != nil {
+		return false, "", err
+	}
+
+	status, reason, _ := apiServiceCondition(*apiservice)
+	if status != affirmative {
+		return false, fmt.Sprintf("APIService %s is not available: %s", name, reason), nil
+	}
+
+	return true, "", nil
+}
+
+// Get the status, reason and message of the Available condition
+func apiServiceCondition(apiservice unstructured.Unstructured) (string, string, string) {
+	conditions, _, _ := unstructured.NestedSlice(apiservice.Object, "status", "conditions")
+	for _, raw := range conditions {
+		condition, ok := raw.(map[string]interface{})
+		if !ok || condition["type"] != "Available" {
+			continue
+		}
+		status, _ := condition["status"].(string)
+		reason, _ := condition["reason"].(string)
+		message, _ := condition["message"].(string)
+		return status, reason, message
+	}
+
+	return "Unknown", "", "Available condition not found"
+}
+
+// Describe the service behind an APIService, local ones are served by the kube-apiserver
+func apiServiceBackend(apiservice unstructured.Unstructured) string {
+	namespace, _, _ := unstructured.NestedString(apiservice.Object, "spec", "service", "namespace")
+	name, found, _ := unstructured.NestedString(apiservice.Object, "spec", "service", "name")
+	if !found {
+		return "Local"
+	}
+	return namespace + "/" + name
+}
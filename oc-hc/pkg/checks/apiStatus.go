@@ -0,0 +1,75 @@
+/*
+Copyright © 2023 Givaldo Lins <gilins@redhat.com>
+*/
+package checks
+
+import (
+	"context"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// APIStatus checks the status of the kube and openshift API
+func APIStatus(ctx context.Context, clients *Clients, opts Options) (*Result, error) {
+	result := &Result{Title: "Checking API..."}
+
+	// Get the pods name for the apiserver
+	apipods, err := clients.Kube.CoreV1().Pods("openshift-apiserver").List(ctx, metav1.ListOptions{LabelSelector: "app=openshift-apiserver-a"})
+	if err != nil {
+		return result, err
+	}
+
+	// Create new tables for the output
+	ocptable := newTable("NAME", "STATUS")
+	kubetable := newTable("NAME", "STATUS")
+
+	// Check the openshift apiserver pods
+	warning := false
+	for _, apipod := range apipods.Items {
+		stdout, execErr := execInPod(ctx, clients, "openshift-apiserver", apipod.GetName(), "openshift-apiserver", "curl", "-s", "-k", "https://localhost:8443/readyz")
+		if execErr != nil {
+			return result, execErr
+		}
+		if stdout != "ok" {
+			ocptable.addRow(apipod.Name, "Not Ready")
+			warning = true
+		} else {
+			ocptable.addRow(apipod.Name, "Ready")
+		}
+	}
+	message := "All API Pods are ready"
+	if warning {
+		message = "There is one or more openshift apiserver pod(s) not ready"
+	}
+	result.add(Section{Title: "Checking OpenShift API server pods readiness...", Status: warningStatus(warning), Message: message, Table: ocptable})
+
+	// Get the pods name for kube apiserver
+	kubepods, err := clients.Kube.CoreV1().Pods("openshift-kube-apiserver").List(ctx, metav1.ListOptions{LabelSelector: "app=openshift-kube-apiserver"})
+	if err != nil {
+		return result, err
+	}
+
+	// Check kube apiserver pods
+	warning = false
+	for _, kubepod := range kubepods.Items {
+		stdout, execErr := execInPod(ctx, clients, "openshift-kube-apiserver", kubepod.GetName(), "kube-apiserver", "curl", "-s", "-k", "https://localhost:6443/readyz")
+		if execErr != nil {
+			return result, execErr
+		}
+
+		if stdout != "ok" {
+			kubetable.addRow(kubepod.Name, "Not Ready")
+			warning = true
+		} else {
+			kubetable.addRow(kubepod.Name, "Ready")
+		}
+	}
+
+	message = "All API Pods are ready"
+	if warning {
+		message = "There is one or more kube apiserver pod(s) not ready"
+	}
+	result.add(Section{Title: "Checking OpenShift Kube API server pods readiness...", Status: warningStatus(warning), Message: message, Table: kubetable})
+
+	return result, nil
+}
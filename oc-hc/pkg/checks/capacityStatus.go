@@ -0,0 +1,131 @@
+/*
+Copyright © 2023 Givaldo Lins <gilins@redhat.com>
+*/
+package checks
+
+import (
+	"context"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Struct for node metrics
+type nodemetrics struct {
+	name      string
+	capCpu    float64
+	capMemory float64
+}
+
+// CapacityStatus checks the nodes allocated resources and current utilization
+func CapacityStatus(ctx context.Context, clients *Clients, opts Options) (*Result, error) {
+	result := &Result{Title: "Checking capacity..."}
+
+	nodes, section, err := allocatableResources(ctx, clients)
+	if err != nil {
+		return result, err
+	}
+	result.add(section)
+
+	section, err = currentUtilization(ctx, clients, nodes)
+	if err != nil {
+		return result, err
+	}
+	result.add(section)
+
+	return result, nil
+}
+
+// Fuction to check allocatable resources
+func allocatableResources(ctx context.Context, clients *Clients) ([]nodemetrics, Section, error) {
+	section := Section{Title: "Checking allocated resources..."}
+
+	// Get a list of nodes
+	nodeList, err := clients.Kube.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, section, err
+	}
+
+	// Create a new table for the output
+	table := newTable("NODENAME", "CPU", "MEMORY")
+
+	rNode := []nodemetrics{}
+
+	// Calculate node allocatable percentage
+	warning := false
+	for _, node := range nodeList.Items {
+		capCpu := node.Status.Capacity.Cpu().AsApproximateFloat64()
+		capMem := node.Status.Capacity.Memory().AsApproximateFloat64()
+		allocCpu := node.Status.Allocatable.Cpu().AsApproximateFloat64()
+		allocMem := node.Status.Allocatable.Memory().AsApproximateFloat64()
+		percentCpu := 100 - (allocCpu * 100 / capCpu)
+		percentMem := 100 - (allocMem * 100 / capMem)
+		rNode = append(rNode, nodemetrics{capCpu: capCpu, capMemory: capMem, name: node.GetName()})
+
+		if percentCpu >= 80 || percentMem >= 80 {
+			warning = true
+		}
+		table.addRow(node.Name, fmt.Sprintf("%.0f%%", percentCpu), fmt.Sprintf("%.0f%%", percentMem))
+	}
+
+	// Set output
+	section.Status = warningStatus(warning)
+	section.Message = "All node have less than 80% CPU or Memory pre-allocation"
+	if warning {
+		section.Message = "There is one or more node(s) with either CPU or Memory pre-allocated over 80%"
+	}
+	section.Table = table
+
+	return rNode, section, nil
+}
+
+// Check node current utilization
+func currentUtilization(ctx context.Context, clients *Clients, nodeList []nodemetrics) (Section, error) {
+	section := Section{Title: "Checking current resources use..."}
+
+	// Skip when the metrics API is not served, if the APIService cannot be read query it anyway
+	available, reason, err := apiServiceAvailable(ctx, clients, metricsAPIService)
+	if err == nil && !available {
+		section.Status = StatusSkipped
+		section.Message = "Metrics API unavailable, " + reason
+		return section, nil
+	}
+
+	// Create a new table for the output
+	table := newTable("NODENAME", "CPU", "MEMORY")
+
+	// Get nodes metrics
+	nodesUtilization, err := clients.Metrics.MetricsV1beta1().NodeMetricses().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return section, err
+	}
+
+	// Calculate node utilization percentage
+	warning := false
+	for _, node := range nodesUtilization.Items {
+		cpuUtilization := node.Usage.Cpu().AsApproximateFloat64()
+		memoryUtilization := node.Usage.Memory().AsApproximateFloat64()
+		for _, item := range nodeList {
+			if node.Name == item.name {
+				percentCPU := cpuUtilization * 100 / item.capCpu
+				percentMemory := memoryUtilization * 100 / item.capMemory
+
+				if percentCPU >= 80 || percentMemory >= 80 {
+					warning = true
+				}
+
+				table.addRow(node.Name, fmt.Sprintf("%.0f%%", percentCPU), fmt.Sprintf("%.0f%%", percentMemory))
+			}
+		}
+	}
+
+	// Set output
+	section.Status = warningStatus(warning)
+	section.Message = "All node have less than 80% CPU or Memory utilization"
+	if warning {
+		section.Message = "There is one or more node(s) with either CPU or Memory utilization over 80%"
+	}
+	section.Table = table
+
+	return section, nil
+}
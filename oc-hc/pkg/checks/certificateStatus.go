@@ -0,0 +1,390 @@
+/*
+Copyright © 2023 Givaldo Lins <gilins@redhat.com>
+*/
+package checks
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// Default certificate expiry windows in days
+const (
+	defaultCertWarningDays  = 30
+	defaultCertCriticalDays = 7
+)
+
+// Fractions of the lifetime left used for certificates rotated by an operator
+const (
+	managedCertWarning  = 0.2
+	managedCertCritical = 0.1
+)
+
+// Certificate states reported as errors
+const (
+	certExpired  = "Expired"
+	certCritical = "Critical"
+)
+
+// Annotations prefix set by the operators on the certificates they rotate
+const managedCertAnnotation = "auth.openshift.io/certificate-"
+
+// GroupVersionResource for ingresscontrollers
+var ingressControllerResource = schema.GroupVersionResource{Group: "operator.openshift.io", Version: "v1", Resource: "ingresscontrollers"}
+
+// ConfigMaps holding CA bundles used by the platform, managed ones hold short lived signers rotated by the operators
+var caBundles = []struct {
+	namespace string
+	name      string
+	key       string
+	managed   bool
+}{
+	{"openshift-config", "user-ca-bundle", "ca-bundle.crt", false},
+	{"openshift-config-managed", "default-ingress-cert", "ca-bundle.crt", true},
+	{"openshift-config-managed", "kube-apiserver-server-ca", "ca-bundle.crt", true},
+	{"openshift-config-managed", "kube-apiserver-client-ca", "ca-bundle.crt", true},
+	{"openshift-kube-apiserver", "trusted-ca-bundle", "ca-bundle.crt", false},
+}
+
+// Struct for the certificate expiry windows, managed certificates are judged by their lifetime left
+type certWindows struct {
+	now      time.Time
+	warning  time.Duration
+	critical time.Duration
+	managed  bool
+}
+
+// CertificateStatus checks for expired or expiring certificates used by the platform
+func CertificateStatus(ctx context.Context, clients *Clients, opts Options) (*Result, error) {
+	result := &Result{Title: "Checking certificates..."}
+
+	windows := newCertWindows(opts.Certificates, time.Now())
+
+	section, err := tlsSecretsCertificates(ctx, clients, opts.Certificates.AllNamespaces, windows)
+	if err != nil {
+		return result, err
+	}
+	result.add(section)
+
+	section, err = ingressCertificates(ctx, clients, windows)
+	if err != nil {
+		return result, err
+	}
+	result.add(section)
+
+	section, err = apiServerCertificates(ctx, clients, windows)
+	if err != nil {
+		return result, err
+	}
+	result.add(section)
+
+	section, err = caBundleCertificates(ctx, clients, windows)
+	if err != nil {
+		return result, err
+	}
+	result.add(section)
+
+	return result, nil
+}
+
+// Check the kubernetes.io/tls secrets in openshift-* namespaces, or in all namespaces
+func tlsSecretsCertificates(ctx context.Context, clients *Clients, allNamespaces bool, windows certWindows) (Section, error) {
+	section := Section{Title: "Checking TLS secrets..."}
+
+	secrets, err := clients.Kube.CoreV1().Secrets("").List(ctx, metav1.ListOptions{FieldSelector: "type=" + string(corev1.SecretTypeTLS)})
+	if err != nil {
+		return section, err
+	}
+
+	// Create a new table for the output
+	table := newCertTable()
+
+	checked := 0
+	for _, secret := range secrets.Items {
+		if !allNamespaces && !strings.HasPrefix(secret.Namespace, "openshift-") {
+			continue
+		}
+		checked++
+
+		// Short lived certificates rotated by an operator are always inside the expiry windows
+		secretWindows := windows
+		secretWindows.managed = operatorManaged(secret)
+		addCertRows(table, fmt.Sprintf("secret/%s/%s", secret.Namespace, secret.Name), secret.Data[corev1.TLSCertKey], secretWindows)
+	}
+
+	// Set output
+	setCertOutput(&section, table, fmt.Sprintf("All certificates in %d TLS secret(s) are valid for more than %d days", checked, windows.warningDays()))
+
+	return section, nil
+}
+
+// Check the default certificate of every ingresscontroller
+func ingressCertificates(ctx context.Context, clients *Clients, windows certWindows) (Section, error) {
+	section := Section{Title: "Checking ingresscontrollers default certificates..."}
+
+	ingresses, err := clients.Dynamic.Resource(ingressControllerResource).Namespace("openshift-ingress-operator").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return section, err
+	}
+
+	// Create a new table for the output
+	table := newCertTable()
+
+	for _, ingress := range ingresses.Items {
+		// Secret generated by the ingress operator unless a custom one is set
+		name := "router-certs-" + ingress.GetName()
+		if custom, found, _ := unstructured.NestedString(ingress.Object, "spec", "defaultCertificate", "name"); found && custom != "" {
+			name = custom
+		}
+		object := "secret/openshift-ingress/" + name
+
+		secret, getErr := clients.Kube.CoreV1().Secrets("openshift-ingress").Get(ctx, name, metav1.GetOptions{})
+		if apierrors.IsNotFound(getErr) {
+			table.addRow(object, "", "", "", "", "Secret not found")
+			continue
+		}
+		if getErr != nil {
+			return section, getErr
+		}
+		addCertRows(table, object, secret.Data[corev1.TLSCertKey], windows)
+	}
+
+	// Set output
+	setCertOutput(&section, table, fmt.Sprintf("All ingresscontrollers default certificates are valid for more than %d days", windows.warningDays()))
+
+	return section, nil
+}
+
+// Check the named certificates of the API server
+func apiServerCertificates(ctx context.Context, clients *Clients, windows certWindows) (Section, error) {
+	section := Section{Title: "Checking API server named certificates..."}
+
+	apiserver, err := clients.Config.ConfigV1().APIServers().Get(ctx, "cluster", metav1.GetOptions{})
+	if err != nil {
+		return section, err
+	}
+
+	namedCertificates := apiserver.Spec.ServingCerts.NamedCertificates
+	if len(namedCertificates) == 0 {
+		section.Status = StatusInfo
+		section.Message = "There is no named certificate configured for the API server"
+		return section, nil
+	}
+
+	// Create a new table for the output
+	table := newCertTable()
+
+	for _, named := range namedCertificates {
+		name := named.ServingCertificate.Name
+		object := "secret/openshift-config/" + name
+		secret, secretErr := clients.Kube.CoreV1().Secrets("openshift-config").Get(ctx, name, metav1.GetOptions{})
+		if apierrors.IsNotFound(secretErr) {
+			table.addRow(object, "", "", strings.Join(named.Names, ","), "", "Secret not found")
+			continue
+		}
+		if secretErr != nil {
+			return section, secretErr
+		}
+		addCertRows(table, object, secret.Data[corev1.TLSCertKey], windows)
+	}
+
+	// Set output
+	setCertOutput(&section, table, fmt.Sprintf("All %d named certificate(s) are valid for more than %d days", len(namedCertificates), windows.warningDays()))
+
+	return section, nil
+}
+
+// Check the CA bundles in the platform configmaps
+func caBundleCertificates(ctx context.Context, clients *Clients, windows certWindows) (Section, error) {
+	section := Section{Title: "Checking CA bundles..."}
+
+	// Create a new table for the output
+	table := newCertTable()
+
+	checked := 0
+	for _, bundle := range caBundles {
+		configmap, err := clients.Kube.CoreV1().ConfigMaps(bundle.namespace).Get(ctx, bundle.name, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return section, err
+		}
+		checked++
+
+		// Retired signers stay in managed bundles until they expire, only the current ones are judged
+		certs := parseCertificates([]byte(configmap.Data[bundle.key]))
+		bundleWindows := windows
+		if bundle.managed {
+			bundleWindows.managed = true
+			certs = currentSigners(certs, windows.now)
+		}
+		addCertificates(table, fmt.Sprintf("configmap/%s/%s", bundle.namespace, bundle.name), certs, bundleWindows)
+	}
+
+	// Set output
+	setCertOutput(&section, table, fmt.Sprintf("All certificates in %d CA bundle(s) are valid for more than %d days", checked, windows.warningDays()))
+
+	return section, nil
+}
+
+// Create a new table for certificates
+func newCertTable() *Table {
+	return newTable("OBJECT", "SUBJECT", "ISSUER", "SANS", "NOT AFTER", "STATE")
+}
+
+// Add a row for every certificate in the PEM data that is expired or inside the expiry windows
+func addCertRows(table *Table, object string, data []byte, windows certWindows) {
+	addCertificates(table, object, parseCertificates(data), windows)
+}
+
+// Add a row for every certificate that is expired or inside the expiry windows
+func addCertificates(table *Table, object string, certs []*x509.Certificate, windows certWindows) {
+	for _, cert := range certs {
+		state := windows.state(cert)
+		if state == "" {
+			continue
+		}
+		sans := append([]string{}, cert.DNSNames...)
+		for _, ip := range cert.IPAddresses {
+			sans = append(sans, ip.String())
+		}
+		table.addRow(object, cert.Subject.CommonName, cert.Issuer.CommonName, truncate(strings.Join(sans, ","), 60), cert.NotAfter.UTC().Format(time.UnixDate), state)
+	}
+}
+
+// Keep the newest certificate of every signer and the expired ones
+func currentSigners(certs []*x509.Certificate, now time.Time) []*x509.Certificate {
+	newest := map[string]*x509.Certificate{}
+	for _, cert := range certs {
+		signer := signerName(cert)
+		if current, found := newest[signer]; !found || cert.NotBefore.After(current.NotBefore) {
+			newest[signer] = cert
+		}
+	}
+
+	signers := []*x509.Certificate{}
+	for _, cert := range certs {
+		if newest[signerName(cert)] == cert || !cert.NotAfter.After(now) {
+			signers = append(signers, cert)
+		}
+	}
+	return signers
+}
+
+// Name of the signer of a certificate, without the @timestamp suffix the operators add on every rotation
+func signerName(cert *x509.Certificate) string {
+	name := cert.Subject.CommonName
+	if i := strings.LastIndex(name, "@"); i >= 0 {
+		if _, err := strconv.ParseInt(name[i+1:], 10, 64); err == nil {
+			return name[:i]
+		}
+	}
+	return name
+}
+
+// Parse all certificates in the PEM data, invalid blocks are ignored
+func parseCertificates(data []byte) []*x509.Certificate {
+	certs := []*x509.Certificate{}
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			return certs
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			continue
+		}
+		certs = append(certs, cert)
+	}
+}
+
+// Set the status and message of a certificate section, expired and critical certificates are errors
+func setCertOutput(section *Section, table *Table, okMsg string) {
+	section.Status = warningStatus(len(table.Rows) > 0)
+	section.Message = okMsg
+	if len(table.Rows) == 0 {
+		return
+	}
+	section.Message = "There is one or more certificates expired or about to expire"
+	section.Table = table
+	for _, row := range table.Rows {
+		state := row[len(row)-1]
+		if strings.HasPrefix(state, certExpired) || strings.HasPrefix(state, certCritical) {
+			section.Status = StatusError
+		}
+	}
+}
+
+// Check if a secret holds a certificate rotated by an operator
+func operatorManaged(secret corev1.Secret) bool {
+	for annotation := range secret.Annotations {
+		if strings.HasPrefix(annotation, managedCertAnnotation) {
+			return true
+		}
+	}
+	return false
+}
+
+// Build the expiry windows from the options
+func newCertWindows(opts CertificateOptions, now time.Time) certWindows {
+	windows := certWindows{now: now, warning: defaultCertWarningDays * 24 * time.Hour, critical: defaultCertCriticalDays * 24 * time.Hour}
+	if opts.WarningDays > 0 {
+		windows.warning = time.Duration(opts.WarningDays) * 24 * time.Hour
+	}
+	if opts.CriticalDays > 0 {
+		windows.critical = time.Duration(opts.CriticalDays) * 24 * time.Hour
+	}
+	return windows
+}
+
+// Describe the certificate state, empty when it is valid for longer than the warning window
+func (w certWindows) state(cert *x509.Certificate) string {
+	left := cert.NotAfter.Sub(w.now)
+	if w.managed {
+		return w.managedState(cert, left)
+	}
+	switch {
+	case left <= 0:
+		return certExpired
+	case left <= w.critical:
+		return fmt.Sprintf("%s, expires in %d day(s)", certCritical, int(left.Hours()/24))
+	case left <= w.warning:
+		return fmt.Sprintf("Expires in %d day(s)", int(left.Hours()/24))
+	}
+	return ""
+}
+
+// Describe the state of a certificate rotated by an operator, empty while enough of its lifetime is left
+func (w certWindows) managedState(cert *x509.Certificate, left time.Duration) string {
+	lifetime := cert.NotAfter.Sub(cert.NotBefore)
+	switch {
+	case left <= 0:
+		return certExpired
+	case float64(left) <= float64(lifetime)*managedCertCritical:
+		return fmt.Sprintf("%s, not rotated, expires in %d day(s)", certCritical, int(left.Hours()/24))
+	case float64(left) <= float64(lifetime)*managedCertWarning:
+		return fmt.Sprintf("Not rotated, expires in %d day(s)", int(left.Hours()/24))
+	}
+	return ""
+}
+
+// Warning window in days
+func (w certWindows) warningDays() int {
+	return int(w.warning.Hours() / 24)
+}
@@ -0,0 +1,63 @@
+/*
+Copyright © 2023 Givaldo Lins <gilins@redhat.com>
+*/
+package checks
+
+import (
+	apiserverset "github.com/openshift/client-go/apiserver/clientset/versioned"
+	configset "github.com/openshift/client-go/config/clientset/versioned"
+	routeset "github.com/openshift/client-go/route/clientset/versioned"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	metricsv1beta "k8s.io/metrics/pkg/client/clientset/versioned"
+)
+
+// Clients used by the checks to interact with the cluster
+type Clients struct {
+	RestConfig *rest.Config
+	Kube       kubernetes.Interface
+	Config     configset.Interface
+	Route      routeset.Interface
+	Metrics    metricsv1beta.Interface
+	Dynamic    dynamic.Interface
+	APIServer  apiserverset.Interface
+}
+
+// NewClients creates all clients used by the checks from a rest config
+func NewClients(config *rest.Config) (*Clients, error) {
+	kube, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	configClient, err := configset.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	route, err := routeset.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	metrics, err := metricsv1beta.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	dynamicClient, err := dynamic.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	apiserver, err := apiserverset.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Clients{
+		RestConfig: config,
+		Kube:       kube,
+		Config:     configClient,
+		Route:      route,
+		Metrics:    metrics,
+		Dynamic:    dynamicClient,
+		APIServer:  apiserver,
+	}, nil
+}
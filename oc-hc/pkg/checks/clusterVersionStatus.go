@@ -0,0 +1,182 @@
+/*
+Copyright © 2023 Givaldo Lins <gilins@redhat.com>
+*/
+package checks
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	configv1 "github.com/openshift/api/config/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Number of update history entries listed by default
+const defaultHistoryLimit = 5
+
+// ClusterVersionStatus checks the clusterversion conditions, update history and available updates
+func ClusterVersionStatus(ctx context.Context, clients *Clients, opts Options) (*Result, error) {
+	result := &Result{Title: "Checking cluster version..."}
+
+	// Get cluster version object
+	clusterversion, err := clients.Config.ConfigV1().ClusterVersions().Get(ctx, "version", metav1.GetOptions{})
+	if err != nil {
+		return result, err
+	}
+
+	result.add(clusterVersionConditions(clusterversion))
+	result.add(upgradeProgress(clusterversion, time.Now()))
+
+	limit := opts.Version.HistoryLimit
+	if limit <= 0 {
+		limit = defaultHistoryLimit
+	}
+	result.add(updateHistory(clusterversion, limit))
+	result.add(availableUpdates(clusterversion))
+
+	return result, nil
+}
+
+// Check the clusterversion conditions
+func clusterVersionConditions(clusterversion *configv1.ClusterVersion) Section {
+	section := Section{Title: "Checking clusterversion conditions..."}
+
+	// Create a new table for the output
+	table := newTable("TYPE", "STATUS", "REASON", "MESSAGE")
+
+	// Conditions reported and the status in which they are unhealthy, Progressing is only reported here
+	watched := []struct {
+		kind      configv1.ClusterStatusConditionType
+		unhealthy configv1.ConditionStatus
+	}{
+		{configv1.OperatorAvailable, configv1.ConditionFalse},
+		{"Failing", configv1.ConditionTrue},
+		{configv1.OperatorProgressing, ""},
+		{configv1.OperatorUpgradeable, configv1.ConditionFalse},
+		{configv1.RetrievedUpdates, configv1.ConditionFalse},
+		{"ReleaseAccepted", configv1.ConditionFalse},
+	}
+
+	warning := false
+	for _, watch := range watched {
+		for _, condition := range clusterversion.Status.Conditions {
+			if condition.Type != watch.kind {
+				continue
+			}
+			if condition.Status == watch.unhealthy {
+				warning = true
+			}
+			table.addRow(string(condition.Type), string(condition.Status), condition.Reason, truncate(condition.Message, 80))
+		}
+	}
+
+	// Set output
+	section.Status = warningStatus(warning)
+	section.Message = "All clusterversion conditions look good"
+	if warning {
+		section.Message = "One or more clusterversion conditions may require your attention"
+	}
+	section.Table = table
+
+	return section
+}
+
+// Check if an upgrade is in progress and for how long
+func upgradeProgress(clusterversion *configv1.ClusterVersion, now time.Time) Section {
+	section := Section{Title: "Checking if an upgrade is in progress..."}
+
+	progressing := false
+	message := ""
+	for _, condition := range clusterversion.Status.Conditions {
+		if condition.Type == configv1.OperatorProgressing && condition.Status == configv1.ConditionTrue {
+			progressing = true
+			message = condition.Message
+		}
+	}
+
+	// The current update is the first history entry without completion time
+	history := clusterversion.Status.History
+	if !progressing || len(history) == 0 || history[0].CompletionTime != nil {
+		section.Status = StatusInfo
+		section.Message = fmt.Sprintf("There is no upgrade in progress, cluster is running version %s", clusterversion.Status.Desired.Version)
+		return section
+	}
+
+	duration := now.Sub(history[0].StartedTime.Time).Round(time.Minute)
+	section.Status = StatusWarning
+	section.Message = fmt.Sprintf("Upgrade to %s in progress for %s: %s", history[0].Version, duration, message)
+
+	return section
+}
+
+// List the last entries of the update history
+func updateHistory(clusterversion *configv1.ClusterVersion, limit int) Section {
+	section := Section{Title: "Checking update history..."}
+
+	// Create a new table for the output
+	table := newTable("VERSION", "STATE", "STARTED", "COMPLETED", "VERIFIED")
+
+	// Partial entries that are not the current update never completed
+	warning := false
+	for i, entry := range clusterversion.Status.History {
+		if i >= limit {
+			break
+		}
+		completed := "<In progress>"
+		if entry.CompletionTime != nil {
+			completed = entry.CompletionTime.UTC().Format(time.UnixDate)
+		}
+		if entry.State == configv1.PartialUpdate && entry.CompletionTime != nil {
+			warning = true
+		}
+		table.addRow(entry.Version, string(entry.State), entry.StartedTime.UTC().Format(time.UnixDate), completed, fmt.Sprint(entry.Verified))
+	}
+
+	// Set output
+	section.Status = warningStatus(warning)
+	section.Message = fmt.Sprintf("Last %d entries of the update history", len(table.Rows))
+	if warning {
+		section.Message = "There is one or more upgrades that were never completely applied"
+	}
+	section.Table = table
+
+	return section
+}
+
+// List the available and conditional updates
+func availableUpdates(clusterversion *configv1.ClusterVersion) Section {
+	section := Section{Title: "Checking available updates..."}
+
+	// Create a new table for the output
+	table := newTable("VERSION", "RECOMMENDED", "RISKS")
+
+	for _, update := range clusterversion.Status.AvailableUpdates {
+		table.addRow(update.Version, affirmative, "")
+	}
+	for _, update := range clusterversion.Status.ConditionalUpdates {
+		recommended := "Unknown"
+		for _, condition := range update.Conditions {
+			if condition.Type == "Recommended" {
+				recommended = string(condition.Status)
+			}
+		}
+		risks := []string{}
+		for _, risk := range update.Risks {
+			risks = append(risks, risk.Name)
+		}
+		table.addRow(update.Release.Version, recommended, strings.Join(risks, ","))
+	}
+
+	// Set output
+	section.Status = StatusInfo
+	if len(table.Rows) == 0 {
+		section.Message = fmt.Sprintf("There is no update available in channel %s", clusterversion.Spec.Channel)
+		return section
+	}
+	section.Message = fmt.Sprintf("There is %d update(s) available in channel %s", len(table.Rows), clusterversion.Spec.Channel)
+	section.Table = table
+
+	return section
+}
@@ -0,0 +1,57 @@
+/*
+Copyright © 2023 Givaldo Lins <gilins@redhat.com>
+*/
+package checks
+
+import (
+	"context"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// CoStatus checks the status of cluster operators
+func CoStatus(ctx context.Context, clients *Clients, opts Options) (*Result, error) {
+	result := &Result{Title: "Checking cluster Operators..."}
+
+	// Get a list of cluster operators
+	clusteroperators, err := clients.Config.ConfigV1().ClusterOperators().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return result, err
+	}
+
+	// Create a new table for the output
+	table := newTable("NAME", "AVAILABLE", "PROGRESSING", "DEGRADED")
+
+	// Check the CO status
+	warning := false
+
+	for _, co := range clusteroperators.Items {
+		available := affirmative
+		progressing := negative
+		degraded := negative
+		for _, condition := range co.Status.Conditions {
+			if condition.Type == "Degraded" && condition.Status == affirmative {
+				warning = true
+				degraded = affirmative
+			}
+			if condition.Type == "Available" && condition.Status == negative {
+				warning = true
+				available = negative
+			}
+			if condition.Type == "Progressing" && condition.Status == affirmative {
+				warning = true
+				progressing = affirmative
+			}
+		}
+		table.addRow(co.Name, available, progressing, degraded)
+	}
+
+	// Set output
+	message := "All clusteroperator are healthy"
+	if warning {
+		message = "One or more clusteroperator(s) is unhealthy"
+	}
+	result.add(Section{Status: warningStatus(warning), Message: message, Table: table})
+
+	return result, nil
+}
@@ -0,0 +1,49 @@
+/*
+Copyright © 2023 Givaldo Lins <gilins@redhat.com>
+*/
+package checks
+
+import (
+	"context"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// CSRStatus checks if there is pending CSRs
+func CSRStatus(ctx context.Context, clients *Clients, opts Options) (*Result, error) {
+	result := &Result{Title: "Checking CSRs status..."}
+
+	// Get a list of CSRs
+	csrs, err := clients.Kube.CertificatesV1().CertificateSigningRequests().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return result, err
+	}
+
+	// Create a new table for the output
+	table := newTable("NAME", "STATUS")
+
+	// Check CSRs
+	warning := false
+csrLoop:
+	for _, csr := range csrs.Items {
+		if len(csr.Status.Conditions) == 0 {
+			warning = true
+			continue csrLoop
+		}
+		for _, condition := range csr.Status.Conditions {
+			if !(condition.Type == "Approved" && condition.Status == affirmative) {
+				warning = true
+				table.addRow(csr.Name, string(condition.Status))
+			}
+		}
+	}
+
+	// Set output
+	if warning {
+		result.add(Section{Status: StatusWarning, Message: "There is one or more CSR not in Approved state", Table: table})
+	} else {
+		result.add(Section{Status: StatusInfo, Message: "There is no pending CSR at this time"})
+	}
+
+	return result, nil
+}
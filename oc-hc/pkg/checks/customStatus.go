@@ -0,0 +1,150 @@
+/*
+Copyright © 2023 Givaldo Lins <gilins@redhat.com>
+*/
+package checks
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/cel-go/cel"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+// CustomCheck is a check based on a CEL expression evaluated for every object of a resource
+type CustomCheck struct {
+	Name          string `mapstructure:"name"`
+	Group         string `mapstructure:"group"`
+	Version       string `mapstructure:"version"`
+	Resource      string `mapstructure:"resource"`
+	Namespace     string `mapstructure:"namespace"`
+	LabelSelector string `mapstructure:"labelSelector"`
+	Expression    string `mapstructure:"expression"`
+	Message       string `mapstructure:"message"`
+	Severity      string `mapstructure:"severity"`
+}
+
+// CustomStatus runs the custom checks, it returns a nil Result when there is no check to run
+func CustomStatus(ctx context.Context, clients *Clients, opts Options) (*Result, error) {
+	if len(opts.CustomChecks) == 0 {
+		return nil, nil
+	}
+
+	result := &Result{Title: "Checking custom rules..."}
+
+	// CEL environment shared by all checks, the object is exposed as "object"
+	env, err := cel.NewEnv(cel.Variable("object", cel.DynType))
+	if err != nil {
+		return result, err
+	}
+
+	for _, check := range opts.CustomChecks {
+		section, checkErr := runCustomCheck(ctx, clients.Dynamic, env, check)
+		if checkErr != nil {
+			result.add(Section{Title: section.Title, Status: StatusError, Message: fmt.Sprintf("Check %s failed: %s", check.Name, checkErr)})
+			continue
+		}
+		result.add(section)
+	}
+
+	return result, nil
+}
+
+// Run a single custom check
+func runCustomCheck(ctx context.Context, client dynamic.Interface, env *cel.Env, check CustomCheck) (Section, error) {
+	section := Section{Title: fmt.Sprintf("Checking %s...", check.Name)}
+
+	// Validate check definition
+	if check.Severity == "" {
+		check.Severity = SeverityWarning
+	}
+	if check.Severity != SeverityCritical && check.Severity != SeverityWarning && check.Severity != SeverityInfo {
+		return section, fmt.Errorf("invalid severity %q for check %q", check.Severity, check.Name)
+	}
+
+	// Compile expressions
+	expression, err := compileCEL(env, check.Expression)
+	if err != nil {
+		return section, fmt.Errorf("check %q: %w", check.Name, err)
+	}
+	var message cel.Program
+	if check.Message != "" {
+		message, err = compileCEL(env, check.Message)
+		if err != nil {
+			return section, fmt.Errorf("check %q: %w", check.Name, err)
+		}
+	}
+
+	// Get a list of objects
+	gvr := schema.GroupVersionResource{Group: check.Group, Version: check.Version, Resource: check.Resource}
+	objects, err := client.Resource(gvr).Namespace(check.Namespace).List(ctx, metav1.ListOptions{LabelSelector: check.LabelSelector})
+	if err != nil {
+		return section, err
+	}
+
+	// Create a new table for the output
+	table := newTable("NAME", "NAMESPACE", "MESSAGE")
+
+	// Evaluate the expression for every object, objects that can not be evaluated are reported as errors
+	warning := false
+	failed := false
+	for _, object := range objects.Items {
+		vars := map[string]interface{}{"object": object.Object}
+		out, _, evalErr := expression.Eval(vars)
+		if evalErr != nil {
+			failed = true
+			table.addRow(object.GetName(), object.GetNamespace(), fmt.Sprintf("evaluation failed: %s", evalErr))
+			continue
+		}
+		passed, ok := out.Value().(bool)
+		if !ok {
+			failed = true
+			table.addRow(object.GetName(), object.GetNamespace(), fmt.Sprintf("evaluation failed: expression must return a bool, got %v", out.Type()))
+			continue
+		}
+		if passed {
+			continue
+		}
+
+		warning = true
+		msg := "expression evaluated to false"
+		if message != nil {
+			msgOut, _, msgErr := message.Eval(vars)
+			if msgErr != nil {
+				msg = fmt.Sprintf("expression evaluated to false, message evaluation failed: %s", msgErr)
+			} else {
+				msg = fmt.Sprint(msgOut.Value())
+			}
+		}
+		table.addRow(object.GetName(), object.GetNamespace(), msg)
+	}
+
+	// Set output
+	switch {
+	case failed:
+		section.Status = StatusError
+		section.Message = fmt.Sprintf("The check could not be evaluated on one or more %s", check.Resource)
+	case warning:
+		section.Status = severityStatus(check.Severity)
+		section.Message = fmt.Sprintf("One or more %s did not pass the check", check.Resource)
+	default:
+		section.Status = StatusInfo
+		section.Message = fmt.Sprintf("All %d %s passed the check", len(objects.Items), check.Resource)
+		return section, nil
+	}
+	section.Table = table
+
+	return section, nil
+}
+
+// Compile a CEL expression into a program
+func compileCEL(env *cel.Env, expression string) (cel.Program, error) {
+	ast, issues := env.Compile(expression)
+	if issues != nil && issues.Err() != nil {
+		return nil, issues.Err()
+	}
+
+	return env.Program(ast)
+}
@@ -0,0 +1,95 @@
+/*
+Copyright © 2023 Givaldo Lins <gilins@redhat.com>
+*/
+package checks
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+)
+
+// Window used by the etcd performance queries by default
+const defaultEtcdWindow = 5 * time.Minute
+
+// Struct for an etcd metric compared against its documented threshold
+type etcdMetric struct {
+	title     string
+	query     string
+	threshold float64
+	unit      string
+}
+
+// EtcdPerformanceStatus checks the etcd disk and network latency from the in-cluster Prometheus
+func EtcdPerformanceStatus(ctx context.Context, clients *Clients, opts Options) (*Result, error) {
+	result := &Result{Title: "Checking ETCD performance..."}
+
+	window := opts.Etcd.Window
+	if window <= 0 {
+		window = defaultEtcdWindow
+	}
+	rangeSelector := fmt.Sprintf("[%ds]", int(window.Seconds()))
+
+	// Thresholds recommended by the etcd and OpenShift documentation
+	metrics := []etcdMetric{
+		{"WAL fsync p99", `histogram_quantile(0.99, sum by (pod, le) (rate(etcd_disk_wal_fsync_duration_seconds_bucket{job="etcd"}` + rangeSelector + `)))`, 0.01, "s"},
+		{"backend commit p99", `histogram_quantile(0.99, sum by (pod, le) (rate(etcd_disk_backend_commit_duration_seconds_bucket{job="etcd"}` + rangeSelector + `)))`, 0.025, "s"},
+		{"peer round trip time p99", `histogram_quantile(0.99, sum by (pod, le) (rate(etcd_network_peer_round_trip_time_seconds_bucket{job="etcd"}` + rangeSelector + `)))`, 0.05, "s"},
+		{"proposal failure rate", `sum by (pod) (rate(etcd_server_proposals_failed_total{job="etcd"}` + rangeSelector + `))`, 0, "/s"},
+	}
+
+	// Get Thanos Querier route and user token
+	host, err := getRouteHost(ctx, clients, "openshift-monitoring", "thanos-querier")
+	if err != nil {
+		return result, err
+	}
+	bearerToken, err := getBearerToken(clients.RestConfig)
+	if err != nil {
+		return result, err
+	}
+
+	for _, metric := range metrics {
+		samples, queryErr := queryPrometheus(ctx, host, bearerToken, metric.query)
+		if queryErr != nil {
+			return result, queryErr
+		}
+
+		// Create a new table for the output
+		table := newTable("POD", "VALUE", "THRESHOLD")
+
+		warning := false
+		for _, sample := range samples {
+			// Histograms without observations in the window return NaN
+			if math.IsNaN(sample.Value) {
+				continue
+			}
+			if sample.Value > metric.threshold {
+				warning = true
+			}
+			table.addRow(sample.Labels["pod"], formatEtcdValue(sample.Value, metric.unit), formatEtcdValue(metric.threshold, metric.unit))
+		}
+
+		// Set output
+		section := Section{Title: fmt.Sprintf("Checking %s...", metric.title), Status: warningStatus(warning), Table: table}
+		section.Message = fmt.Sprintf("ETCD %s is below %s on all members over the last %s", metric.title, formatEtcdValue(metric.threshold, metric.unit), window)
+		if warning {
+			section.Message = fmt.Sprintf("ETCD %s is above %s on one or more members over the last %s", metric.title, formatEtcdValue(metric.threshold, metric.unit), window)
+		}
+		if len(table.Rows) == 0 {
+			section.Message = fmt.Sprintf("There is no ETCD %s sample over the last %s", metric.title, window)
+			section.Table = nil
+		}
+		result.add(section)
+	}
+
+	return result, nil
+}
+
+// Format latencies in milliseconds and rates with three decimals
+func formatEtcdValue(value float64, unit string) string {
+	if unit == "s" {
+		return fmt.Sprintf("%.1fms", value*1000)
+	}
+	return fmt.Sprintf("%.3f%s", value, unit)
+}
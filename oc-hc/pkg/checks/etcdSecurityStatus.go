@@ -0,0 +1,150 @@
+/*
+Copyright © 2023 Givaldo Lins <gilins@redhat.com>
+*/
+package checks
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	configv1 "github.com/openshift/api/config/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// Maximum age of the last successful etcd backup by default
+const defaultBackupMaxAge = 24 * time.Hour
+
+// Operators that report the Encrypted condition
+var encryptionOperators = []schema.GroupVersionResource{
+	{Group: "operator.openshift.io", Version: "v1", Resource: "openshiftapiservers"},
+	{Group: "operator.openshift.io", Version: "v1", Resource: "kubeapiservers"},
+}
+
+// EtcdSecurityStatus checks the etcd encryption and backup posture
+func EtcdSecurityStatus(ctx context.Context, clients *Clients, opts Options) (*Result, error) {
+	result := &Result{Title: "Checking ETCD encryption and backup..."}
+
+	section, err := etcdEncryption(ctx, clients)
+	if err != nil {
+		return result, err
+	}
+	result.add(section)
+
+	section, err = etcdBackup(ctx, clients, opts.Etcd, time.Now())
+	if err != nil {
+		return result, err
+	}
+	result.add(section)
+
+	return result, nil
+}
+
+// Check the encryption type and the Encrypted condition of the API server operators
+func etcdEncryption(ctx context.Context, clients *Clients) (Section, error) {
+	section := Section{Title: "Checking ETCD encryption..."}
+
+	apiserver, err := clients.Config.ConfigV1().APIServers().Get(ctx, "cluster", metav1.GetOptions{})
+	if err != nil {
+		return section, err
+	}
+
+	encryption := apiserver.Spec.Encryption.Type
+	if encryption == "" || encryption == configv1.EncryptionTypeIdentity {
+		section.Status = StatusWarning
+		section.Message = "ETCD encryption is not enabled"
+		return section, nil
+	}
+
+	// Create a new table for the output
+	table := newTable("OPERATOR", "ENCRYPTED", "REASON", "MESSAGE")
+
+	warning := false
+	for _, gvr := range encryptionOperators {
+		operator, getErr := clients.Dynamic.Resource(gvr).Get(ctx, "cluster", metav1.GetOptions{})
+		if getErr != nil {
+			return section, getErr
+		}
+		conditions, _, _ := unstructured.NestedSlice(operator.Object, "status", "conditions")
+
+		status, reason, message := "Unknown", "", "Encrypted condition not found"
+		for _, raw := range conditions {
+			condition, ok := raw.(map[string]interface{})
+			if !ok || condition["type"] != "Encrypted" {
+				continue
+			}
+			status, _ = condition["status"].(string)
+			reason, _ = condition["reason"].(string)
+			message, _ = condition["message"].(string)
+		}
+
+		// Migration is completed when the condition is True with EncryptionCompleted reason
+		if status != affirmative || reason != "EncryptionCompleted" {
+			warning = true
+		}
+		table.addRow(gvr.Resource+"/cluster", status, reason, truncate(message, 80))
+	}
+
+	// Set output
+	section.Status = warningStatus(warning)
+	section.Message = fmt.Sprintf("ETCD encryption is enabled with %s and the migration is completed", encryption)
+	if warning {
+		section.Message = fmt.Sprintf("ETCD encryption is enabled with %s but the migration is not completed", encryption)
+	}
+	section.Table = table
+
+	return section, nil
+}
+
+// Check the last successful job of the etcd backup CronJob
+func etcdBackup(ctx context.Context, clients *Clients, opts EtcdOptions, now time.Time) (Section, error) {
+	section := Section{Title: "Checking ETCD backup..."}
+
+	if opts.BackupCronJob == "" {
+		section.Status = StatusInfo
+		section.Message = "There is no ETCD backup CronJob configured, skipping"
+		return section, nil
+	}
+	if opts.BackupNamespace == "" {
+		section.Status = StatusSkipped
+		section.Message = fmt.Sprintf("There is no namespace configured for the ETCD backup CronJob %s", opts.BackupCronJob)
+		return section, nil
+	}
+	maxAge := opts.BackupMaxAge
+	if maxAge <= 0 {
+		maxAge = defaultBackupMaxAge
+	}
+	object := fmt.Sprintf("cronjob/%s/%s", opts.BackupNamespace, opts.BackupCronJob)
+
+	cronjob, err := clients.Kube.BatchV1().CronJobs(opts.BackupNamespace).Get(ctx, opts.BackupCronJob, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		section.Status = StatusWarning
+		section.Message = fmt.Sprintf("ETCD backup %s not found", object)
+		return section, nil
+	}
+	if err != nil {
+		return section, err
+	}
+
+	// Set output
+	switch {
+	case cronjob.Status.LastSuccessfulTime == nil:
+		section.Status = StatusWarning
+		section.Message = fmt.Sprintf("ETCD backup %s never completed successfully", object)
+	case now.Sub(cronjob.Status.LastSuccessfulTime.Time) > maxAge:
+		section.Status = StatusWarning
+		section.Message = fmt.Sprintf("Last successful ETCD backup by %s is %s old, older than %s", object, now.Sub(cronjob.Status.LastSuccessfulTime.Time).Round(time.Minute), maxAge)
+	default:
+		section.Status = StatusInfo
+		section.Message = fmt.Sprintf("Last successful ETCD backup by %s completed at %s", object, cronjob.Status.LastSuccessfulTime.UTC().Format(time.UnixDate))
+	}
+	if cronjob.Spec.Suspend != nil && *cronjob.Spec.Suspend {
+		section.Status = StatusWarning
+		section.Message += ", the CronJob is suspended"
+	}
+
+	return section, nil
+}
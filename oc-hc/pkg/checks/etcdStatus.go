@@ -0,0 +1,329 @@
+/*
+Copyright © 2023 Givaldo Lins <gilins@redhat.com>
+*/
+package checks
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	configv1 "github.com/openshift/api/config/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// etcd thresholds
+const (
+	// Raft index difference between members that is reported as lag
+	maxRaftIndexLag = 100
+	// Fragmentation and minimum database size used by the etcd operator to defragment
+	maxFragmentation = 45
+	minDefragSize    = 100 * 1024 * 1024
+	// Default backend quota of OpenShift etcd and the usage reported as high
+	etcdQuota     = 8 * 1024 * 1024 * 1024
+	maxQuotaUsage = 80
+)
+
+// Where etcd runs
+const (
+	etcdNamespace  = "openshift-etcd"
+	etcdContainer  = "etcd"
+	etcdClientPort = "2379"
+)
+
+// Struct for the etcdctl member list output
+type etcdMemberList struct {
+	Members []struct {
+		ID       uint64   `json:"ID"`
+		Name     string   `json:"name"`
+		PeerURLs []string `json:"peerURLs"`
+	} `json:"members"`
+}
+
+// Struct for the etcdctl endpoint status output
+type etcdEndpointStatus struct {
+	Endpoint string `json:"Endpoint"`
+	Status   struct {
+		Header struct {
+			MemberID uint64 `json:"member_id"`
+		} `json:"header"`
+		Version     string `json:"version"`
+		DBSize      int64  `json:"dbSize"`
+		DBSizeInUse int64  `json:"dbSizeInUse"`
+		Leader      uint64 `json:"leader"`
+		RaftIndex   uint64 `json:"raftIndex"`
+		RaftTerm    uint64 `json:"raftTerm"`
+	} `json:"Status"`
+}
+
+// EtcdStatus checks the ETCD health, members, leader and database
+func EtcdStatus(ctx context.Context, clients *Clients, opts Options) (*Result, error) {
+	result := &Result{Title: "Checking ETCD..."}
+
+	// Get the ETCD status
+	etcdpods, err := clients.Kube.CoreV1().Pods(etcdNamespace).List(ctx, metav1.ListOptions{LabelSelector: "app=etcd"})
+	if err != nil {
+		return result, err
+	}
+
+	// Create a new table for the output
+	table := newTable("NAME", "HEALTHY")
+
+	// Check ETCD
+	warning := false
+	running := ""
+	for _, etcd := range etcdpods.Items {
+		// Check liveness
+		stdout, execErr := execInPod(ctx, clients, etcdNamespace, etcd.Name, etcdContainer, "curl", "-k", "-s", "-o", "/dev/null", "-w%{http_code}", "https://localhost:9980/healthz")
+		if execErr != nil {
+			return result, execErr
+		}
+		if stdout != "200" {
+			table.addRow(etcd.Name, negative)
+			warning = true
+		} else {
+			table.addRow(etcd.Name, affirmative)
+		}
+		if running == "" && etcd.Status.Phase == corev1.PodRunning {
+			running = etcd.Name
+		}
+	}
+
+	// Set output
+	message := "All ETCD member are Healthy"
+	if warning {
+		message = "One or more ETCD member is degraded"
+	}
+	result.add(Section{Status: warningStatus(warning), Message: message, Table: table})
+
+	if running == "" {
+		return result, errors.New("there is no running etcd pod")
+	}
+
+	// Get members and their status from etcdctl
+	members := etcdMemberList{}
+	stdout, err := execInPod(ctx, clients, etcdNamespace, running, etcdContainer, "etcdctl", "member", "list", "-w", "json")
+	if err != nil {
+		return result, err
+	}
+	if err = json.Unmarshal([]byte(stdout), &members); err != nil {
+		return result, err
+	}
+	statuses := []etcdEndpointStatus{}
+	stdout, err = execInPod(ctx, clients, etcdNamespace, running, etcdContainer, "etcdctl", "endpoint", "status", "--cluster", "-w", "json")
+	if err != nil {
+		return result, err
+	}
+	if err = json.Unmarshal([]byte(stdout), &statuses); err != nil {
+		return result, err
+	}
+
+	section, err := etcdMembers(ctx, clients, members)
+	if err != nil {
+		return result, err
+	}
+	result.add(section)
+
+	section, err = etcdLeader(ctx, clients, etcdpods.Items, members, statuses)
+	if err != nil {
+		return result, err
+	}
+	result.add(section)
+
+	result.add(etcdDatabase(members, statuses))
+
+	section, err = etcdMembersAvailable(ctx, clients)
+	if err != nil {
+		return result, err
+	}
+	result.add(section)
+
+	return result, nil
+}
+
+// Compare the member list with the control plane nodes
+func etcdMembers(ctx context.Context, clients *Clients, members etcdMemberList) (Section, error) {
+	section := Section{Title: "Checking ETCD members..."}
+
+	nodes, err := clients.Kube.CoreV1().Nodes().List(ctx, metav1.ListOptions{LabelSelector: "node-role.kubernetes.io/master"})
+	if err != nil {
+		return section, err
+	}
+
+	// Create a new table for the output
+	table := newTable("MEMBER", "ID", "PEER URLS")
+	for _, member := range members.Members {
+		table.addRow(member.Name, fmt.Sprintf("%x", member.ID), strings.Join(member.PeerURLs, ","))
+	}
+
+	// Set output
+	warning := len(members.Members) != len(nodes.Items)
+	section.Status = warningStatus(warning)
+	section.Message = fmt.Sprintf("There are %d ETCD members for %d control plane nodes", len(members.Members), len(nodes.Items))
+	if warning {
+		section.Message = fmt.Sprintf("There are %d ETCD members but %d control plane nodes", len(members.Members), len(nodes.Items))
+	}
+	section.Table = table
+
+	return section, nil
+}
+
+// Check the leader, leader changes and raft index lag between members
+func etcdLeader(ctx context.Context, clients *Clients, pods []corev1.Pod, members etcdMemberList, statuses []etcdEndpointStatus) (Section, error) {
+	section := Section{Title: "Checking ETCD leader..."}
+
+	// Leader changes are read from the metrics of every member since it started
+	leaderChanges := map[string]string{}
+	for _, pod := range pods {
+		if pod.Status.Phase != corev1.PodRunning {
+			continue
+		}
+		changes, err := etcdLeaderChanges(ctx, clients, pod.Name)
+		if err != nil {
+			return section, err
+		}
+		leaderChanges[strings.TrimPrefix(pod.Name, "etcd-")] = changes
+	}
+
+	maxIndex := uint64(0)
+	leaders := map[uint64]bool{}
+	for _, status := range statuses {
+		if status.Status.RaftIndex > maxIndex {
+			maxIndex = status.Status.RaftIndex
+		}
+		leaders[status.Status.Leader] = true
+	}
+
+	// Create a new table for the output
+	table := newTable("MEMBER", "LEADER", "RAFT TERM", "RAFT INDEX", "INDEX LAG", "LEADER CHANGES")
+
+	lagging := false
+	for _, status := range statuses {
+		name := etcdMemberName(members, status.Status.Header.MemberID)
+		leader := negative
+		if status.Status.Leader == status.Status.Header.MemberID {
+			leader = affirmative
+		}
+		lag := maxIndex - status.Status.RaftIndex
+		if lag > maxRaftIndexLag {
+			lagging = true
+		}
+		table.addRow(name, leader, fmt.Sprint(status.Status.RaftTerm), fmt.Sprint(status.Status.RaftIndex), fmt.Sprint(lag), leaderChanges[name])
+	}
+
+	// Set output
+	section.Table = table
+	switch {
+	case len(leaders) != 1 || leaders[0]:
+		section.Status = StatusWarning
+		section.Message = "ETCD members do not agree on a leader"
+	case lagging:
+		section.Status = StatusWarning
+		section.Message = fmt.Sprintf("One or more ETCD members are more than %d raft entries behind", maxRaftIndexLag)
+	default:
+		section.Status = StatusInfo
+		section.Message = "ETCD members agree on the leader and are in sync"
+	}
+
+	return section, nil
+}
+
+// Get the number of leader changes seen by a member from its metrics
+func etcdLeaderChanges(ctx context.Context, clients *Clients, pod string) (string, error) {
+	metrics, err := execInPod(ctx, clients, etcdNamespace, pod, etcdContainer, "sh", "-c",
+		`curl -s --cacert "$ETCDCTL_CACERT" --cert "$ETCDCTL_CERT" --key "$ETCDCTL_KEY" https://localhost:`+etcdClientPort+`/metrics`)
+	if err != nil {
+		return "", err
+	}
+
+	scanner := bufio.NewScanner(strings.NewReader(metrics))
+	for scanner.Scan() {
+		if value, found := strings.CutPrefix(scanner.Text(), "etcd_server_leader_changes_seen_total "); found {
+			return value, nil
+		}
+	}
+
+	return "Unknown", nil
+}
+
+// Check the database size and fragmentation
+func etcdDatabase(members etcdMemberList, statuses []etcdEndpointStatus) Section {
+	section := Section{Title: "Checking ETCD database..."}
+
+	// Create a new table for the output
+	table := newTable("MEMBER", "DB SIZE", "IN USE", "FRAGMENTATION", "QUOTA USAGE")
+
+	fragmented := false
+	full := false
+	for _, status := range statuses {
+		size := status.Status.DBSize
+		fragmentation := int64(0)
+		if size > 0 {
+			fragmentation = (size - status.Status.DBSizeInUse) * 100 / size
+		}
+		if fragmentation >= maxFragmentation && size >= minDefragSize {
+			fragmented = true
+		}
+		usage := size * 100 / etcdQuota
+		if usage >= maxQuotaUsage {
+			full = true
+		}
+		table.addRow(etcdMemberName(members, status.Status.Header.MemberID),
+			resource.NewQuantity(size, resource.BinarySI).String(),
+			resource.NewQuantity(status.Status.DBSizeInUse, resource.BinarySI).String(),
+			fmt.Sprintf("%d%%", fragmentation),
+			fmt.Sprintf("%d%%", usage))
+	}
+
+	// Set output
+	section.Table = table
+	switch {
+	case full:
+		section.Status = StatusWarning
+		section.Message = fmt.Sprintf("One or more ETCD databases use more than %d%% of the quota", maxQuotaUsage)
+	case fragmented:
+		section.Status = StatusWarning
+		section.Message = fmt.Sprintf("One or more ETCD databases are more than %d%% fragmented, a defragmentation is recommended", maxFragmentation)
+	default:
+		section.Status = StatusInfo
+		section.Message = "ETCD database size and fragmentation look good"
+	}
+
+	return section
+}
+
+// Check the EtcdMembersAvailable condition of the etcd clusteroperator
+func etcdMembersAvailable(ctx context.Context, clients *Clients) (Section, error) {
+	section := Section{Title: "Checking ETCD clusteroperator..."}
+
+	co, err := clients.Config.ConfigV1().ClusterOperators().Get(ctx, "etcd", metav1.GetOptions{})
+	if err != nil {
+		return section, err
+	}
+
+	section.Status = StatusWarning
+	section.Message = "EtcdMembersAvailable condition not found in the etcd clusteroperator"
+	for _, condition := range co.Status.Conditions {
+		if condition.Type == "EtcdMembersAvailable" {
+			section.Status = warningStatus(condition.Status != configv1.ConditionTrue)
+			section.Message = fmt.Sprintf("EtcdMembersAvailable is %s: %s", condition.Status, condition.Message)
+		}
+	}
+
+	return section, nil
+}
+
+// Find the name of a member from its ID
+func etcdMemberName(members etcdMemberList, id uint64) string {
+	for _, member := range members.Members {
+		if member.ID == id {
+			return member.Name
+		}
+	}
+	return fmt.Sprintf("%x", id)
+}
@@ -0,0 +1,51 @@
+/*
+Copyright © 2023 Givaldo Lins <gilins@redhat.com>
+*/
+package checks
+
+import (
+	"context"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// EventStatus checks existing warning events across the cluster
+func EventStatus(ctx context.Context, clients *Clients, opts Options) (*Result, error) {
+	result := &Result{Title: "Checking events..."}
+
+	// Get all events
+	events, err := clients.Kube.CoreV1().Events("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return result, err
+	}
+
+	// Create a new table for the output
+	table := newTable("LAST EVENT TIME", "REASON", "OBJECT", "MESSAGE")
+
+	warning := false
+	var object, lasteventtime string
+	for _, event := range events.Items {
+		if event.Type == "Warning" {
+			object = event.InvolvedObject.Kind + "/" + event.InvolvedObject.Name
+			warning = true
+			if event.LastTimestamp.IsZero() {
+				lasteventtime = "<Unknown>"
+			} else {
+				lasteventtime = event.LastTimestamp.UTC().Format(time.UnixDate)
+			}
+			if len(event.Message) < 80 {
+				table.addRow(lasteventtime, event.Reason, object, event.Message)
+			} else {
+				table.addRow(lasteventtime, event.Reason, object, event.Message[:80]+"...")
+			}
+		}
+	}
+	if warning {
+		result.add(Section{Status: StatusWarning, Message: "There is one or more events that may require your attention", Table: table})
+	} else {
+		result.add(Section{Status: StatusInfo, Message: "There is no warning events at this time"})
+	}
+
+	return result, nil
+}
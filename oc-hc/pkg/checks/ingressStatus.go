@@ -0,0 +1,241 @@
+/*
+Copyright © 2023 Givaldo Lins <gilins@redhat.com>
+*/
+package checks
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	routev1 "github.com/openshift/api/route/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+// Label set by the ingress operator on router pods
+const routerDeploymentLabel = "ingresscontroller.operator.openshift.io/deployment-ingresscontroller"
+
+// IngressStatus checks the ingresscontrollers and their router pods
+func IngressStatus(ctx context.Context, clients *Clients, opts Options) (result *Result, err error) {
+	result = &Result{Title: "Checking ingress controllers..."}
+
+	ingresses, err := clients.Dynamic.Resource(ingressControllerResource).Namespace("openshift-ingress-operator").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return result, err
+	}
+
+	result.add(ingressConditions(ingresses.Items))
+
+	section, err := routerSpread(ctx, clients, ingresses.Items)
+	if err != nil {
+		return result, err
+	}
+	result.add(section)
+
+	if !opts.Ingress.TestRoute {
+		return result, nil
+	}
+
+	// The test route lives in a temporary namespace that is always deleted
+	tester, err := newNetworkTester(ctx, clients, opts.Network)
+	defer func() {
+		if cleanupErr := tester.cleanup(); cleanupErr != nil && err == nil {
+			err = cleanupErr
+		}
+	}()
+	if err != nil {
+		return result, err
+	}
+	section, err = testRoute(ctx, clients, tester)
+	if err != nil {
+		return result, err
+	}
+	result.add(section)
+
+	return result, nil
+}
+
+// Check the conditions, replicas and endpoint publishing strategy of every ingresscontroller
+func ingressConditions(ingresses []unstructured.Unstructured) Section {
+	section := Section{Title: "Checking ingresscontrollers status..."}
+
+	// Create a new table for the output
+	table := newTable("NAME", "DOMAIN", "STRATEGY", "AVAILABLE", "DEGRADED", "REPLICAS")
+
+	warning := false
+	for _, ingress := range ingresses {
+		domain, _, _ := unstructured.NestedString(ingress.Object, "status", "domain")
+		strategy, _, _ := unstructured.NestedString(ingress.Object, "status", "endpointPublishingStrategy", "type")
+		desired, found, _ := unstructured.NestedInt64(ingress.Object, "spec", "replicas")
+		if !found {
+			desired, _, _ = unstructured.NestedInt64(ingress.Object, "status", "availableReplicas")
+		}
+		available, _, _ := unstructured.NestedInt64(ingress.Object, "status", "availableReplicas")
+
+		availableCondition, degradedCondition := "Unknown", "Unknown"
+		conditions, _, _ := unstructured.NestedSlice(ingress.Object, "status", "conditions")
+		for _, raw := range conditions {
+			condition, ok := raw.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			status, _ := condition["status"].(string)
+			switch condition["type"] {
+			case "Available":
+				availableCondition = status
+			case "Degraded":
+				degradedCondition = status
+			}
+		}
+
+		if availableCondition != affirmative || degradedCondition != negative || available < desired {
+			warning = true
+		}
+		table.addRow(ingress.GetName(), domain, strategy, availableCondition, degradedCondition, fmt.Sprintf("%d/%d", available, desired))
+	}
+
+	// Set output
+	section.Status = warningStatus(warning)
+	section.Message = "All ingresscontrollers are available"
+	if warning {
+		section.Message = "One or more ingresscontrollers are unavailable, degraded or missing replicas"
+	}
+	section.Table = table
+
+	return section
+}
+
+// Check that the router pods of every ingresscontroller are spread across nodes and zones
+func routerSpread(ctx context.Context, clients *Clients, ingresses []unstructured.Unstructured) (Section, error) {
+	section := Section{Title: "Checking router pods spread..."}
+
+	nodes, err := clients.Kube.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return section, err
+	}
+	nodeZones := map[string]string{}
+	clusterZones := map[string]bool{}
+	for _, node := range nodes.Items {
+		zone := node.Labels[corev1.LabelTopologyZone]
+		nodeZones[node.Name] = zone
+		if zone != "" {
+			clusterZones[zone] = true
+		}
+	}
+
+	// Create a new table for the output
+	table := newTable("NAME", "READY PODS", "NODES", "ZONES")
+
+	warning := false
+	for _, ingress := range ingresses {
+		pods, listErr := clients.Kube.CoreV1().Pods("openshift-ingress").List(ctx, metav1.ListOptions{LabelSelector: routerDeploymentLabel + "=" + ingress.GetName()})
+		if listErr != nil {
+			return section, listErr
+		}
+		ready := 0
+		podNodes := map[string]bool{}
+		podZones := map[string]bool{}
+		for _, pod := range pods.Items {
+			if !podReady(pod) {
+				continue
+			}
+			ready++
+			podNodes[pod.Spec.NodeName] = true
+			if zone := nodeZones[pod.Spec.NodeName]; zone != "" {
+				podZones[zone] = true
+			}
+		}
+
+		// Routers sharing a node, or a single zone on multi zone clusters, are a single point of failure
+		if len(podNodes) < ready || (ready > 1 && len(clusterZones) > 1 && len(podZones) < 2) {
+			warning = true
+		}
+		table.addRow(ingress.GetName(), fmt.Sprint(ready), fmt.Sprint(len(podNodes)), fmt.Sprint(len(podZones)))
+	}
+
+	// Set output
+	section.Status = warningStatus(warning)
+	section.Message = "Router pods are spread across nodes and zones"
+	if warning {
+		section.Message = "Router pods of one or more ingresscontrollers share nodes or zones"
+	}
+	section.Table = table
+
+	return section, nil
+}
+
+// Create a temporary route and check that it is admitted and reachable
+func testRoute(ctx context.Context, clients *Clients, tester *networkTester) (Section, error) {
+	section := Section{Title: "Checking a temporary route..."}
+
+	// A service without endpoints is enough, the router answers for the route with its own page
+	service := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "route-probe", Namespace: tester.namespace},
+		Spec:       corev1.ServiceSpec{Ports: []corev1.ServicePort{{Port: 8080, TargetPort: intstr.FromInt(8080)}}},
+	}
+	_, err := clients.Kube.CoreV1().Services(tester.namespace).Create(ctx, service, metav1.CreateOptions{})
+	if err != nil {
+		return section, err
+	}
+	route := &routev1.Route{
+		ObjectMeta: metav1.ObjectMeta{Name: "route-probe", Namespace: tester.namespace},
+		Spec:       routev1.RouteSpec{To: routev1.RouteTargetReference{Kind: "Service", Name: "route-probe"}},
+	}
+	_, err = clients.Route.RouteV1().Routes(tester.namespace).Create(ctx, route, metav1.CreateOptions{})
+	if err != nil {
+		return section, err
+	}
+
+	// Wait for the route to be admitted by the default router
+	var admitted *routev1.Route
+	err = wait.PollUntilContextTimeout(ctx, 2*time.Second, time.Minute, true, func(ctx context.Context) (bool, error) {
+		current, getErr := clients.Route.RouteV1().Routes(tester.namespace).Get(ctx, "route-probe", metav1.GetOptions{})
+		if getErr != nil {
+			return false, getErr
+		}
+		for _, ingress := range current.Status.Ingress {
+			for _, condition := range ingress.Conditions {
+				if condition.Type == routev1.RouteAdmitted && condition.Status == corev1.ConditionTrue {
+					admitted = current
+					return true, nil
+				}
+			}
+		}
+		return false, nil
+	})
+	if err != nil && ctx.Err() != nil {
+		return section, err
+	}
+	if admitted == nil {
+		section.Status = StatusWarning
+		section.Message = "Temporary route was not admitted within 1 minute"
+		if err != nil && !wait.Interrupted(err) {
+			section.Message = fmt.Sprintf("Temporary route could not be checked: %s", err)
+		}
+		return section, nil
+	}
+
+	// Any answer from the router means the route is reachable from here
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://"+admitted.Spec.Host, nil)
+	if err != nil {
+		return section, err
+	}
+	start := time.Now()
+	response, err := (&http.Client{Timeout: 10 * time.Second}).Do(request)
+	if err != nil {
+		section.Status = StatusWarning
+		section.Message = fmt.Sprintf("Temporary route %s was admitted but is not reachable: %s", admitted.Spec.Host, err)
+		return section, nil
+	}
+	response.Body.Close()
+
+	section.Status = StatusInfo
+	section.Message = fmt.Sprintf("Temporary route %s was admitted and the router answered in %s", admitted.Spec.Host, time.Since(start).Round(time.Millisecond))
+
+	return section, nil
+}
@@ -0,0 +1,103 @@
+/*
+Copyright © 2023 Givaldo Lins <gilins@redhat.com>
+*/
+package checks
+
+import (
+	"context"
+	"strconv"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// GroupVersionResource for machineConfigPools
+var mcpResource = schema.GroupVersionResource{Group: "machineconfiguration.openshift.io", Version: "v1", Resource: "machineconfigpools"}
+
+// Structs for machineConfigPool
+type mcpResponse struct {
+	Items []struct {
+		Spec     *mcpSpec     `json:"spec"`
+		Status   *mcpStatus   `json:"status"`
+		Metadata *mcpMetadata `json:"metadata"`
+	} `json:"items"`
+}
+type mcpMetadata struct {
+	Name string `json:"name"`
+}
+type mcpSpec struct {
+	Paused       bool                  `json:"paused"`
+	NodeSelector *metav1.LabelSelector `json:"nodeSelector"`
+}
+type mcpConditions struct {
+	Type    string `json:"type"`
+	Status  string `json:"status"`
+	Message string `json:"message"`
+}
+type mcpStatus struct {
+	Conditions           []*mcpConditions `json:"conditions"`
+	DegradedMachineCount int              `json:"degradedMachineCount"`
+	MachineCount         int              `json:"machineCount"`
+	ReadyMachineCount    int              `json:"readyMachineCount"`
+	UpdatedMachineCount  int              `json:"updatedMachineCount"`
+}
+
+// MachineConfigPoolStatus checks the MCP
+func MachineConfigPoolStatus(ctx context.Context, clients *Clients, opts Options) (*Result, error) {
+	result := &Result{Title: "Checking MCP..."}
+
+	// Get MCPs
+	data, err := listMachineConfigPools(ctx, clients)
+	if err != nil {
+		return result, err
+	}
+
+	// Create a new table for the output
+	table := newTable("NAME", "UPDATING", "DEGRADED", "MACHINECOUNT", "READYMACHINECOUNT", "UPDATEDMACHINECOUNT", "DEGRADEDMACHINECOUNT")
+
+	// Check MCP status
+	warning := false
+	for _, mcp := range data.Items {
+		updating := negative
+		degraded := negative
+		for _, condition := range mcp.Status.Conditions {
+			if condition.Type == "Updating" && condition.Status == affirmative {
+				warning = true
+				updating = affirmative
+			}
+
+			if condition.Type == "Degraded" || condition.Type == "NodeDegrade" || condition.Type == "RenderDegraded" {
+				if condition.Status == affirmative {
+					warning = true
+					degraded = affirmative
+				}
+			}
+		}
+		table.addRow(mcp.Metadata.Name, updating, degraded, strconv.Itoa(mcp.Status.MachineCount), strconv.Itoa(mcp.Status.ReadyMachineCount), strconv.Itoa(mcp.Status.UpdatedMachineCount), strconv.Itoa(mcp.Status.DegradedMachineCount))
+	}
+
+	// Set output
+	message := "All machineconfigpools look good"
+	if warning {
+		message = "One or more machineconfigpool may require your attention"
+	}
+	result.add(Section{Title: "Checking if MCP is rolling the nodes...", Status: warningStatus(warning), Message: message, Table: table})
+
+	return result, nil
+}
+
+// Get the list of MCPs
+func listMachineConfigPools(ctx context.Context, clients *Clients) (*mcpResponse, error) {
+	list, err := clients.Dynamic.Resource(mcpResource).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	data := &mcpResponse{}
+	err = runtime.DefaultUnstructuredConverter.FromUnstructured(list.UnstructuredContent(), data)
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
@@ -0,0 +1,255 @@
+/*
+Copyright © 2023 Givaldo Lins <gilins@redhat.com>
+*/
+package checks
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+
+	configv1 "github.com/openshift/api/config/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/yaml"
+)
+
+// GroupVersionResource for the cluster network operator config
+var networkOperatorResource = schema.GroupVersionResource{Group: "operator.openshift.io", Version: "v1", Resource: "networks"}
+
+// Internal join subnet used by OVN-Kubernetes when none is configured
+const defaultOVNJoinSubnet = "100.64.0.0/16"
+
+// Struct for the networking section of the install-config
+type installConfig struct {
+	Networking struct {
+		MachineNetwork []struct {
+			CIDR string `json:"cidr"`
+		} `json:"machineNetwork"`
+	} `json:"networking"`
+}
+
+// Struct for a named CIDR compared for overlaps
+type namedCIDR struct {
+	name string
+	cidr string
+}
+
+// NetworkConfigStatus checks the cluster network configuration and the CNI pods
+func NetworkConfigStatus(ctx context.Context, clients *Clients, opts Options) (*Result, error) {
+	result := &Result{Title: "Checking cluster network configuration..."}
+
+	network, err := clients.Config.ConfigV1().Networks().Get(ctx, "cluster", metav1.GetOptions{})
+	if err != nil {
+		return result, err
+	}
+
+	// Migration and the OVN join subnet are set in the operator config
+	operator, err := clients.Dynamic.Resource(networkOperatorResource).Get(ctx, "cluster", metav1.GetOptions{})
+	if err != nil {
+		return result, err
+	}
+
+	result.add(networkConfiguration(network, operator))
+
+	section, err := cniDaemonSet(ctx, clients, network.Status.NetworkType)
+	if err != nil {
+		return result, err
+	}
+	result.add(section)
+
+	section, err = cidrOverlaps(ctx, clients, network, operator)
+	if err != nil {
+		return result, err
+	}
+	result.add(section)
+
+	return result, nil
+}
+
+// Report the network type, CIDRs, MTU and migration status
+func networkConfiguration(network *configv1.Network, operator *unstructured.Unstructured) Section {
+	section := Section{Title: "Checking network type and migration..."}
+
+	// Migration is requested in the operator config and reported in the cluster config status
+	requested, _, _ := unstructured.NestedString(operator.Object, "spec", "migration", "networkType")
+
+	migration := "None"
+	switch {
+	case network.Status.Migration != nil && network.Status.Migration.NetworkType != "":
+		migration = fmt.Sprintf("In progress to %s", network.Status.Migration.NetworkType)
+	case network.Status.Migration != nil && network.Status.Migration.MTU != nil:
+		migration = "MTU migration in progress"
+	case requested != "":
+		migration = fmt.Sprintf("Requested to %s", requested)
+	}
+
+	clusterNetworks := []string{}
+	for _, entry := range network.Status.ClusterNetwork {
+		clusterNetworks = append(clusterNetworks, fmt.Sprintf("%s (/%d per node)", entry.CIDR, entry.HostPrefix))
+	}
+
+	// Create a new table for the output
+	table := newTable("NETWORK TYPE", "CLUSTER NETWORK", "SERVICE NETWORK", "MTU", "MIGRATION")
+	table.addRow(network.Status.NetworkType, strings.Join(clusterNetworks, ","), strings.Join(network.Status.ServiceNetwork, ","), fmt.Sprint(network.Status.ClusterNetworkMTU), migration)
+
+	// Set output
+	warning := migration != "None"
+	section.Status = warningStatus(warning)
+	section.Message = fmt.Sprintf("Cluster network type is %s", network.Status.NetworkType)
+	if warning {
+		section.Message = "A network migration is in progress"
+	}
+	if network.Spec.NetworkType != network.Status.NetworkType {
+		section.Status = StatusWarning
+		section.Message = fmt.Sprintf("Network type %s is configured but %s is running", network.Spec.NetworkType, network.Status.NetworkType)
+	}
+	section.Table = table
+
+	return section
+}
+
+// Check that the CNI DaemonSet pods are ready on every node
+func cniDaemonSet(ctx context.Context, clients *Clients, networkType string) (Section, error) {
+	namespace, name := "openshift-ovn-kubernetes", "ovnkube-node"
+	if networkType == "OpenShiftSDN" {
+		namespace, name = "openshift-sdn", "sdn"
+	}
+	section := Section{Title: fmt.Sprintf("Checking %s DaemonSet...", name)}
+
+	daemonset, err := clients.Kube.AppsV1().DaemonSets(namespace).Get(ctx, name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		section.Status = StatusWarning
+		section.Message = fmt.Sprintf("DaemonSet %s/%s not found for network type %s", namespace, name, networkType)
+		return section, nil
+	}
+	if err != nil {
+		return section, err
+	}
+	selector, err := metav1.LabelSelectorAsSelector(daemonset.Spec.Selector)
+	if err != nil {
+		return section, err
+	}
+	pods, err := clients.Kube.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return section, err
+	}
+	nodes, err := clients.Kube.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return section, err
+	}
+
+	// Create a new table for the output
+	table := newTable("NODE", "POD", "READY")
+
+	for _, node := range nodes.Items {
+		pod, ready := "<none>", negative
+		for _, current := range pods.Items {
+			if current.Spec.NodeName != node.Name {
+				continue
+			}
+			pod = current.Name
+			if podReady(current) {
+				ready = affirmative
+			}
+		}
+		if ready == negative {
+			table.addRow(node.Name, pod, ready)
+		}
+	}
+
+	// Set output
+	section.Status = warningStatus(len(table.Rows) > 0)
+	section.Message = fmt.Sprintf("%s pods are ready on all %d nodes", name, len(nodes.Items))
+	if len(table.Rows) > 0 {
+		section.Message = fmt.Sprintf("%s pods are not ready on one or more nodes", name)
+		section.Table = table
+	}
+
+	return section, nil
+}
+
+// Check for overlaps between the cluster, service and machine networks
+func cidrOverlaps(ctx context.Context, clients *Clients, network *configv1.Network, operator *unstructured.Unstructured) (Section, error) {
+	section := Section{Title: "Checking CIDR overlaps..."}
+
+	cidrs := []namedCIDR{}
+	for _, entry := range network.Status.ClusterNetwork {
+		cidrs = append(cidrs, namedCIDR{"cluster network", entry.CIDR})
+	}
+	for _, cidr := range network.Status.ServiceNetwork {
+		cidrs = append(cidrs, namedCIDR{"service network", cidr})
+	}
+	if network.Status.NetworkType == "OVNKubernetes" {
+		cidrs = append(cidrs, namedCIDR{"OVN join subnet", ovnJoinSubnet(operator)})
+	}
+
+	// Machine network is only recorded in the install-config
+	configmap, err := clients.Kube.CoreV1().ConfigMaps("kube-system").Get(ctx, "cluster-config-v1", metav1.GetOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return section, err
+	}
+	if err == nil {
+		config := installConfig{}
+		if err = yaml.Unmarshal([]byte(configmap.Data["install-config"]), &config); err != nil {
+			return section, err
+		}
+		for _, machine := range config.Networking.MachineNetwork {
+			cidrs = append(cidrs, namedCIDR{"machine network", machine.CIDR})
+		}
+	}
+
+	// Create a new table for the output
+	table := newTable("NETWORK", "CIDR", "OVERLAPS WITH", "CIDR")
+
+	for i := range cidrs {
+		for j := i + 1; j < len(cidrs); j++ {
+			overlap, overlapErr := cidrsOverlap(cidrs[i].cidr, cidrs[j].cidr)
+			if overlapErr != nil {
+				return section, overlapErr
+			}
+			if overlap {
+				table.addRow(cidrs[i].name, cidrs[i].cidr, cidrs[j].name, cidrs[j].cidr)
+			}
+		}
+	}
+
+	// Set output
+	section.Status = warningStatus(len(table.Rows) > 0)
+	section.Message = fmt.Sprintf("There is no overlap between the %d cluster, service and machine CIDRs", len(cidrs))
+	if len(table.Rows) > 0 {
+		section.Message = "There is one or more overlapping CIDRs"
+		section.Table = table
+	}
+
+	return section, nil
+}
+
+// Get the OVN-Kubernetes join subnet from the operator config, newer releases moved it under ipv4
+func ovnJoinSubnet(operator *unstructured.Unstructured) string {
+	ovn := []string{"spec", "defaultNetwork", "ovnKubernetesConfig"}
+	if subnet, _, _ := unstructured.NestedString(operator.Object, append(ovn, "ipv4", "internalJoinSubnet")...); subnet != "" {
+		return subnet
+	}
+	if subnet, _, _ := unstructured.NestedString(operator.Object, append(ovn, "v4InternalSubnet")...); subnet != "" {
+		return subnet
+	}
+	return defaultOVNJoinSubnet
+}
+
+// Check if two CIDRs overlap
+func cidrsOverlap(a string, b string) (bool, error) {
+	_, first, err := net.ParseCIDR(a)
+	if err != nil {
+		return false, err
+	}
+	_, second, err := net.ParseCIDR(b)
+	if err != nil {
+		return false, err
+	}
+
+	return first.Contains(second.IP) || second.Contains(first.IP), nil
+}
@@ -0,0 +1,307 @@
+/*
+Copyright © 2023 Givaldo Lins <gilins@redhat.com>
+*/
+package checks
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+// Connectivity matrix settings
+const (
+	matrixName       = "matrix-probe"
+	matrixPort       = 8080
+	kubeletPort      = 10250
+	defaultMatrixMax = 10
+)
+
+// Function used by the probe pods to measure the TCP time to connect reported by curl, in seconds.
+// Probe pods close every connection and the kubelet answers https, so curl returns right after connecting.
+const matrixProbeFunc = `probe() { h=$2; [[ $h == *:* ]] && h="[$h]"; t=$(curl -sk -o /dev/null --connect-timeout 5 --max-time 10 -w '%{time_connect}' "$4://$h:$3" </dev/null); if [[ -n $t && $t != 0.000000 ]]; then echo "$1=$t"; else echo "$1=Failed"; fi; }`
+
+// Struct for a node covered by the matrix
+type matrixNode struct {
+	name   string
+	nodeIP string
+	pod    string
+	podIP  string
+}
+
+// Deploy a DaemonSet of probe pods and test pod-to-pod, pod-to-service and pod-to-node connectivity between nodes
+func checkConnectivityMatrix(ctx context.Context, clients *Clients, tester *networkTester) ([]Section, error) {
+	podSection := Section{Title: "Checking pod-to-pod and pod-to-service connectivity..."}
+	nodeSection := Section{Title: "Checking pod-to-node host network connectivity..."}
+
+	serviceIP, err := deployMatrix(ctx, clients, tester)
+	if err != nil {
+		return nil, err
+	}
+	nodes, err := matrixNodes(ctx, clients, tester)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create new tables for the output, with a column for every destination node
+	names := matrixNodeNames(nodes)
+	header := append([]string{"FROM \\ TO"}, names...)
+	podTable := newTable(append(header, "SERVICE")...)
+	nodeTable := newTable(header...)
+
+	podWarning := false
+	nodeWarning := false
+	for s, source := range nodes {
+		podRow := []string{names[s]}
+		nodeRow := []string{names[s]}
+
+		// Nodes without a ready probe pod can only be destinations of the host network probes
+		if source.pod == "" {
+			podWarning = true
+			nodeWarning = true
+			podTable.addRow(fillRow(podRow, len(nodes)+1, "No probe pod")...)
+			nodeTable.addRow(fillRow(nodeRow, len(nodes), "No probe pod")...)
+			continue
+		}
+
+		script := []string{matrixProbeFunc}
+		for i, destination := range nodes {
+			if destination.podIP != "" {
+				script = append(script, fmt.Sprintf("probe pod%d %s %d telnet", i, destination.podIP, matrixPort))
+			}
+			script = append(script, fmt.Sprintf("probe node%d %s %d https", i, destination.nodeIP, kubeletPort))
+		}
+		script = append(script, fmt.Sprintf("probe service %s %d telnet", serviceIP, matrixPort))
+
+		// A probe pod that went away only fails its own row
+		stdout, execErr := execInPod(ctx, clients, tester.namespace, source.pod, matrixName, "bash", "-c", strings.Join(script, "\n"))
+		if execErr != nil {
+			if ctx.Err() != nil {
+				return nil, execErr
+			}
+			podWarning = true
+			nodeWarning = true
+			podTable.addRow(fillRow(podRow, len(nodes)+1, "Failed")...)
+			nodeTable.addRow(fillRow(nodeRow, len(nodes), "Failed")...)
+			continue
+		}
+		results := parseMatrixOutput(stdout)
+
+		for i, destination := range nodes {
+			value := "No probe pod"
+			if destination.podIP != "" {
+				value = results[fmt.Sprintf("pod%d", i)]
+			}
+			if value == "Failed" || value == "No probe pod" {
+				podWarning = true
+			}
+			podRow = append(podRow, value)
+
+			value = results[fmt.Sprintf("node%d", i)]
+			if value == "Failed" {
+				nodeWarning = true
+			}
+			nodeRow = append(nodeRow, value)
+		}
+		if results["service"] == "Failed" {
+			podWarning = true
+		}
+		podTable.addRow(append(podRow, results["service"])...)
+		nodeTable.addRow(nodeRow...)
+	}
+
+	// Set output
+	podSection.Status = warningStatus(podWarning)
+	podSection.Message = fmt.Sprintf("Pods can reach each other and the service across %d node(s), times to connect are shown", len(nodes))
+	if podWarning {
+		podSection.Message = "One or more pods can not reach other pods or the service"
+	}
+	podSection.Table = podTable
+
+	nodeSection.Status = warningStatus(nodeWarning)
+	nodeSection.Message = fmt.Sprintf("Pods can reach the host network of %d node(s) on port %d, times to connect are shown", len(nodes), kubeletPort)
+	if nodeWarning {
+		nodeSection.Message = fmt.Sprintf("One or more pods can not reach the host network of other nodes on port %d", kubeletPort)
+	}
+	nodeSection.Table = nodeTable
+
+	return []Section{podSection, nodeSection}, nil
+}
+
+// Create the DaemonSet of probe pods and the service in front of them, it returns the service IP
+func deployMatrix(ctx context.Context, clients *Clients, tester *networkTester) (string, error) {
+	podLabels := map[string]string{networkTesterLabel: matrixName}
+
+	// Probe pods listen on a port that closes every connection, which is enough to measure the time to connect
+	container := corev1.Container{
+		Name:            matrixName,
+		Image:           tester.opts.Image,
+		Command:         []string{"/bin/bash", "-c", fmt.Sprintf("ncat -lk %d --sh-exec true", matrixPort)},
+		Env:             tester.env,
+		SecurityContext: restrictedSecurityContext(),
+		ReadinessProbe: &corev1.Probe{ProbeHandler: corev1.ProbeHandler{
+			TCPSocket: &corev1.TCPSocketAction{Port: intstr.FromInt(matrixPort)},
+		}},
+	}
+	daemonset := &appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{Name: matrixName, Namespace: tester.namespace},
+		Spec: appsv1.DaemonSetSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: podLabels},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: podLabels},
+				Spec: corev1.PodSpec{
+					NodeSelector: tester.opts.NodeSelector,
+					Tolerations:  []corev1.Toleration{{Operator: corev1.TolerationOpExists}},
+					Containers:   []corev1.Container{container},
+				},
+			},
+		},
+	}
+	if tester.opts.PullSecret != "" {
+		daemonset.Spec.Template.Spec.ImagePullSecrets = []corev1.LocalObjectReference{{Name: "oc-hc-pull-secret"}}
+	}
+	_, err := clients.Kube.AppsV1().DaemonSets(tester.namespace).Create(ctx, daemonset, metav1.CreateOptions{})
+	if err != nil {
+		return "", err
+	}
+
+	service := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: matrixName, Namespace: tester.namespace},
+		Spec: corev1.ServiceSpec{
+			Selector: podLabels,
+			Ports:    []corev1.ServicePort{{Port: matrixPort, TargetPort: intstr.FromInt(matrixPort)}},
+		},
+	}
+	service, err = clients.Kube.CoreV1().Services(tester.namespace).Create(ctx, service, metav1.CreateOptions{})
+	if err != nil {
+		return "", err
+	}
+
+	// Wait for the probe pods, nodes where they are not ready are reported by the caller
+	err = wait.PollUntilContextTimeout(ctx, 2*time.Second, tester.opts.Timeout, true, func(ctx context.Context) (bool, error) {
+		current, getErr := clients.Kube.AppsV1().DaemonSets(tester.namespace).Get(ctx, matrixName, metav1.GetOptions{})
+		if getErr != nil {
+			return false, getErr
+		}
+		return current.Status.DesiredNumberScheduled > 0 && current.Status.NumberReady == current.Status.DesiredNumberScheduled, nil
+	})
+	if err != nil && (ctx.Err() != nil || !wait.Interrupted(err)) {
+		return "", err
+	}
+
+	return service.Spec.ClusterIP, nil
+}
+
+// Find the nodes covered by the matrix and their probe pods, sampled when there are too many
+func matrixNodes(ctx context.Context, clients *Clients, tester *networkTester) ([]matrixNode, error) {
+	nodeList, err := clients.Kube.CoreV1().Nodes().List(ctx, metav1.ListOptions{LabelSelector: labels.SelectorFromSet(tester.opts.NodeSelector).String()})
+	if err != nil {
+		return nil, err
+	}
+	pods, err := clients.Kube.CoreV1().Pods(tester.namespace).List(ctx, metav1.ListOptions{LabelSelector: networkTesterLabel + "=" + matrixName})
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := []matrixNode{}
+	for _, node := range nodeList.Items {
+		matrix := matrixNode{name: node.Name}
+		for _, address := range node.Status.Addresses {
+			if address.Type == corev1.NodeInternalIP {
+				matrix.nodeIP = address.Address
+				break
+			}
+		}
+		for _, pod := range pods.Items {
+			if pod.Spec.NodeName == node.Name && podReady(pod) {
+				matrix.pod = pod.Name
+				matrix.podIP = pod.Status.PodIP
+			}
+		}
+		nodes = append(nodes, matrix)
+	}
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].name < nodes[j].name
+	})
+
+	// Sample the nodes evenly to keep the matrix readable
+	limit := tester.opts.MatrixMaxNodes
+	if limit <= 0 {
+		limit = defaultMatrixMax
+	}
+	if len(nodes) > limit {
+		sampled := []matrixNode{}
+		for i := 0; i < limit; i++ {
+			sampled = append(sampled, nodes[i*len(nodes)/limit])
+		}
+		nodes = sampled
+	}
+
+	return nodes, nil
+}
+
+// Parse the probe output into a map of destination to time to connect
+func parseMatrixOutput(stdout string) map[string]string {
+	results := map[string]string{}
+	scanner := bufio.NewScanner(strings.NewReader(stdout))
+	for scanner.Scan() {
+		key, value, found := strings.Cut(scanner.Text(), "=")
+		if !found {
+			continue
+		}
+		if seconds, err := strconv.ParseFloat(value, 64); err == nil {
+			value = fmt.Sprintf("%.1fms", seconds*1000)
+		}
+		results[key] = value
+	}
+
+	return results
+}
+
+// Append the same value to a row for every column
+func fillRow(row []string, columns int, value string) []string {
+	for i := 0; i < columns; i++ {
+		row = append(row, value)
+	}
+	return row
+}
+
+// Check if the pod Ready condition is True
+func podReady(pod corev1.Pod) bool {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// Keep only the host part of the node names to keep the matrix narrow, unless they collide
+func matrixNodeNames(nodes []matrixNode) []string {
+	names := []string{}
+	seen := map[string]bool{}
+	for _, node := range nodes {
+		short, _, _ := strings.Cut(node.name, ".")
+		if seen[short] {
+			names = []string{}
+			for _, full := range nodes {
+				names = append(names, full.name)
+			}
+			return names
+		}
+		seen[short] = true
+		names = append(names, short)
+	}
+	return names
+}
@@ -0,0 +1,88 @@
+/*
+Copyright © 2023 Givaldo Lins <gilins@redhat.com>
+*/
+package checks
+
+import (
+	"context"
+	"fmt"
+)
+
+// NetworkStatus runs additional network checks
+func NetworkStatus(ctx context.Context, clients *Clients, opts Options) (result *Result, err error) {
+	result = &Result{Title: "Checking network..."}
+
+	targets := opts.Network.Targets
+	if len(targets) == 0 {
+		targets = defaultNetworkTargets
+	}
+	script, err := targetsScript(targets)
+	if err != nil {
+		return result, err
+	}
+
+	// Tester pods run in a temporary namespace that is always deleted
+	tester, err := newNetworkTester(ctx, clients, opts.Network)
+	defer func() {
+		if cleanupErr := tester.cleanup(); cleanupErr != nil && err == nil {
+			err = cleanupErr
+		}
+	}()
+	if err != nil {
+		return result, err
+	}
+
+	// Probes from every node replace the single tester pod
+	var section Section
+	if opts.Network.ProbeEach != "" {
+		section, err = checkNodeProbes(ctx, clients, tester, opts.Network.ProbeEach, targets, script)
+	} else {
+		section, err = checkTargets(ctx, tester, targets, script)
+	}
+	if err != nil {
+		return result, err
+	}
+	result.add(section)
+
+	if opts.Network.Matrix {
+		sections, matrixErr := checkConnectivityMatrix(ctx, clients, tester)
+		if matrixErr != nil {
+			return result, matrixErr
+		}
+		for _, matrixSection := range sections {
+			result.add(matrixSection)
+		}
+	}
+
+	return result, nil
+}
+
+// Run a pod that probes all targets
+func checkTargets(ctx context.Context, tester *networkTester, targets []NetworkTarget, script string) (Section, error) {
+	section := Section{Title: "Checking connectivity to the network targets..."}
+
+	logs, err := tester.run(ctx, "target-tester", script)
+	if err != nil {
+		return section, err
+	}
+	results, ok := targetsResults(logs, targets)
+
+	// Create a new table for the output
+	table := newTable("TARGET", "TYPE", "ADDRESS", "RESULT")
+	for i, target := range targets {
+		table.addRow(target.Name, target.Type, target.Address, results[i])
+	}
+
+	// Set output
+	section.Status = warningStatus(!ok)
+	section.Message = "All network targets are reachable"
+	if !ok {
+		section.Message = "One or more network targets are not reachable"
+	}
+	if tester.proxy != "" {
+		section.Message += fmt.Sprintf(", HTTP targets were tested through the cluster proxy %s", tester.proxy)
+	}
+	section.Table = table
+
+	return section, nil
+}
@@ -0,0 +1,112 @@
+/*
+Copyright © 2023 Givaldo Lins <gilins@redhat.com>
+*/
+package checks
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// Types of network probe targets
+const (
+	TargetDNS  = "dns"
+	TargetTCP  = "tcp"
+	TargetHTTP = "http"
+)
+
+// NetworkTarget is a destination probed by the network checks
+type NetworkTarget struct {
+	Name string `mapstructure:"name"`
+	// dns, tcp or http
+	Type string `mapstructure:"type"`
+	// Name to resolve, host:port or URL according to the type
+	Address string `mapstructure:"address"`
+	// HTTP status code expected, any response is accepted when it is 0
+	ExpectedStatus int `mapstructure:"expectedStatus"`
+}
+
+// Targets probed when none is configured
+var defaultNetworkTargets = []NetworkTarget{
+	{Name: "external DNS", Type: TargetDNS, Address: "www.redhat.com"},
+	{Name: "egress", Type: TargetHTTP, Address: "https://www.redhat.com"},
+	{Name: "internal DNS", Type: TargetDNS, Address: "kubernetes.default.svc.cluster.local"},
+	{Name: "API service", Type: TargetHTTP, Address: "https://kubernetes.default.svc/healthz"},
+}
+
+// Build the script that probes all targets, it prints index=result for every target
+func targetsScript(targets []NetworkTarget) (string, error) {
+	lines := []string{}
+	for i, target := range targets {
+		switch target.Type {
+		case TargetDNS:
+			// Short service names are resolved through the search domains of the pod.
+			// dig prints its errors on stdout too, so only answer records and its exit status count.
+			lines = append(lines, fmt.Sprintf(`out=$(dig +search +noall +answer %s) && echo "$out" | grep -v '^;' | grep -q . && echo %d=OK || echo %d=Failed`, shellQuote(target.Address), i, i))
+		case TargetTCP:
+			host, port, err := net.SplitHostPort(target.Address)
+			if err != nil {
+				return "", fmt.Errorf("target %q: %w", target.Name, err)
+			}
+			probe := fmt.Sprintf("</dev/tcp/%s/%s", host, port)
+			lines = append(lines, fmt.Sprintf(`timeout 10 bash -c %s 2>/dev/null && echo %d=OK || echo %d=Failed`, shellQuote(probe), i, i))
+		case TargetHTTP:
+			// curl honors the proxy environment set from the cluster Proxy
+			lines = append(lines, fmt.Sprintf(`echo %d=$(curl -sk -o /dev/null --max-time 10 -w '%%{http_code}' %s)`, i, shellQuote(target.Address)))
+		default:
+			return "", fmt.Errorf("target %q: invalid type %q, expected %s, %s or %s", target.Name, target.Type, TargetDNS, TargetTCP, TargetHTTP)
+		}
+	}
+	lines = append(lines, "exit 0")
+
+	return strings.Join(lines, "\n"), nil
+}
+
+// Parse the script output into one result per target, it reports if all targets succeeded
+func targetsResults(logs string, targets []NetworkTarget) ([]string, bool) {
+	raw := map[int]string{}
+	scanner := bufio.NewScanner(strings.NewReader(logs))
+	for scanner.Scan() {
+		index, value, found := strings.Cut(scanner.Text(), "=")
+		if !found {
+			continue
+		}
+		i, err := strconv.Atoi(index)
+		if err != nil {
+			continue
+		}
+		raw[i] = value
+	}
+
+	results := []string{}
+	ok := true
+	for i, target := range targets {
+		value, found := raw[i]
+		switch {
+		case !found:
+			value = "No result"
+			ok = false
+		case target.Type != TargetHTTP:
+			ok = ok && value == "OK"
+		case value == "000":
+			value = "Unreachable"
+			ok = false
+		case target.ExpectedStatus != 0 && value != strconv.Itoa(target.ExpectedStatus):
+			value = fmt.Sprintf("HTTP %s, expected %d", value, target.ExpectedStatus)
+			ok = false
+		default:
+			value = "HTTP " + value
+		}
+		results = append(results, value)
+	}
+
+	return results, ok
+}
+
+// Quote a string to be used as a single shell word
+func shellQuote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
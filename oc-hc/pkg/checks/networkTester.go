@@ -0,0 +1,248 @@
+/*
+Copyright © 2023 Givaldo Lins <gilins@redhat.com>
+*/
+package checks
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+// Defaults used by the network tester pods
+const (
+	defaultNetworkImage   = "registry.redhat.io/openshift4/network-tools-rhel8"
+	defaultNetworkTimeout = 2 * time.Minute
+	networkTesterLabel    = "oc-hc/network-tester"
+	sccUIDRangeAnnotation = "openshift.io/sa.scc.uid-range"
+)
+
+// Struct for the temporary namespace where the network tester pods run
+type networkTester struct {
+	clients   *Clients
+	opts      NetworkOptions
+	namespace string
+	// Cluster-wide proxy passed to the pods, empty when there is none
+	proxy string
+	env   []corev1.EnvVar
+}
+
+// Create a temporary namespace for the network tester pods, cleanup must always be called
+func newNetworkTester(ctx context.Context, clients *Clients, opts NetworkOptions) (*networkTester, error) {
+	if opts.Image == "" {
+		opts.Image = defaultNetworkImage
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = defaultNetworkTimeout
+	}
+	tester := &networkTester{clients: clients, opts: opts}
+
+	// Egress is tested through the cluster-wide proxy when one is configured
+	proxy, err := clients.Config.ConfigV1().Proxies().Get(ctx, "cluster", metav1.GetOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return tester, err
+	}
+	if err == nil && (proxy.Status.HTTPProxy != "" || proxy.Status.HTTPSProxy != "") {
+		tester.proxy = proxy.Status.HTTPSProxy
+		if tester.proxy == "" {
+			tester.proxy = proxy.Status.HTTPProxy
+		}
+		for _, name := range []string{"HTTP_PROXY", "http_proxy"} {
+			tester.env = append(tester.env, corev1.EnvVar{Name: name, Value: proxy.Status.HTTPProxy})
+		}
+		for _, name := range []string{"HTTPS_PROXY", "https_proxy"} {
+			tester.env = append(tester.env, corev1.EnvVar{Name: name, Value: proxy.Status.HTTPSProxy})
+		}
+		for _, name := range []string{"NO_PROXY", "no_proxy"} {
+			tester.env = append(tester.env, corev1.EnvVar{Name: name, Value: proxy.Status.NoProxy})
+		}
+	}
+
+	namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+		GenerateName: "oc-hc-network-",
+		Labels:       map[string]string{networkTesterLabel: affirmative},
+	}}
+	namespace, err = clients.Kube.CoreV1().Namespaces().Create(ctx, namespace, metav1.CreateOptions{})
+	if err != nil {
+		return tester, err
+	}
+	tester.namespace = namespace.Name
+
+	// Pods are rejected by SCC admission until the namespace is fully set up
+	err = tester.waitNamespace(ctx)
+	if err != nil {
+		return tester, err
+	}
+
+	// Copy the pull secret to the temporary namespace
+	if opts.PullSecret != "" {
+		err = tester.copyPullSecret(ctx)
+		if err != nil {
+			return tester, err
+		}
+	}
+
+	return tester, nil
+}
+
+// Wait for the default ServiceAccount and the SCC annotations of the temporary namespace
+func (t *networkTester) waitNamespace(ctx context.Context) error {
+	err := wait.PollUntilContextTimeout(ctx, time.Second, time.Minute, true, func(ctx context.Context) (bool, error) {
+		namespace, getErr := t.clients.Kube.CoreV1().Namespaces().Get(ctx, t.namespace, metav1.GetOptions{})
+		if getErr != nil {
+			return false, getErr
+		}
+		if namespace.Annotations[sccUIDRangeAnnotation] == "" {
+			return false, nil
+		}
+		_, getErr = t.clients.Kube.CoreV1().ServiceAccounts(t.namespace).Get(ctx, "default", metav1.GetOptions{})
+		if apierrors.IsNotFound(getErr) {
+			return false, nil
+		}
+		return getErr == nil, getErr
+	})
+	if err != nil {
+		return fmt.Errorf("namespace %s is not ready for pods: %w", t.namespace, err)
+	}
+
+	return nil
+}
+
+// Copy the pull secret informed as namespace/name to the temporary namespace
+func (t *networkTester) copyPullSecret(ctx context.Context) error {
+	namespace, name, found := strings.Cut(t.opts.PullSecret, "/")
+	if !found {
+		return fmt.Errorf("invalid pull secret %q, expected namespace/name", t.opts.PullSecret)
+	}
+	secret, err := t.clients.Kube.CoreV1().Secrets(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	copied := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "oc-hc-pull-secret", Namespace: t.namespace},
+		Type:       secret.Type,
+		Data:       secret.Data,
+	}
+	_, err = t.clients.Kube.CoreV1().Secrets(t.namespace).Create(ctx, copied, metav1.CreateOptions{})
+
+	return err
+}
+
+// Run a script in a new pod, wait for it to finish and return its logs
+func (t *networkTester) run(ctx context.Context, name string, script string) (string, error) {
+	err := t.start(ctx, name, "", script)
+	if err != nil {
+		return "", err
+	}
+
+	finished, err := t.wait(ctx, name)
+	if err != nil {
+		return "", err
+	}
+	if !finished[name] {
+		return "", fmt.Errorf("pod %s/%s did not finish in %s", t.namespace, name, t.opts.Timeout)
+	}
+
+	return t.logs(ctx, name)
+}
+
+// Create a pod that runs a script, on the given node when it is not empty
+func (t *networkTester) start(ctx context.Context, name string, nodeName string, script string) error {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: t.namespace, Labels: map[string]string{networkTesterLabel: name}},
+		Spec: corev1.PodSpec{
+			RestartPolicy: corev1.RestartPolicyNever,
+			NodeSelector:  t.opts.NodeSelector,
+			Tolerations:   t.opts.Tolerations,
+			Containers: []corev1.Container{{
+				Name:            "tester",
+				Image:           t.opts.Image,
+				Command:         []string{"/bin/bash", "-c", script},
+				Env:             t.env,
+				SecurityContext: restrictedSecurityContext(),
+			}},
+		},
+	}
+	if t.opts.PullSecret != "" {
+		pod.Spec.ImagePullSecrets = []corev1.LocalObjectReference{{Name: "oc-hc-pull-secret"}}
+	}
+
+	// Pods bound to a node skip the scheduler, the node was already picked with the node selector
+	if nodeName != "" {
+		pod.Spec.NodeName = nodeName
+		pod.Spec.NodeSelector = nil
+		pod.Spec.Tolerations = []corev1.Toleration{{Operator: corev1.TolerationOpExists}}
+	}
+
+	_, err := t.clients.Kube.CoreV1().Pods(t.namespace).Create(ctx, pod, metav1.CreateOptions{})
+
+	return err
+}
+
+// Wait for the pods to finish, it returns which ones finished before the timeout
+func (t *networkTester) wait(ctx context.Context, names ...string) (map[string]bool, error) {
+	finished := map[string]bool{}
+
+	err := wait.PollUntilContextTimeout(ctx, 2*time.Second, t.opts.Timeout, true, func(ctx context.Context) (bool, error) {
+		done := true
+		for _, name := range names {
+			if finished[name] {
+				continue
+			}
+			current, getErr := t.clients.Kube.CoreV1().Pods(t.namespace).Get(ctx, name, metav1.GetOptions{})
+			if getErr != nil {
+				return false, getErr
+			}
+			finished[name] = current.Status.Phase == corev1.PodSucceeded || current.Status.Phase == corev1.PodFailed
+			done = done && finished[name]
+		}
+		return done, nil
+	})
+
+	// Pods still running after the timeout are reported by the caller
+	if err != nil && ctx.Err() == nil && wait.Interrupted(err) {
+		err = nil
+	}
+
+	return finished, err
+}
+
+// Get the logs of a pod
+func (t *networkTester) logs(ctx context.Context, name string) (string, error) {
+	logs, err := t.clients.Kube.CoreV1().Pods(t.namespace).GetLogs(name, &corev1.PodLogOptions{}).DoRaw(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	return string(logs), nil
+}
+
+// Delete the temporary namespace and everything in it, even when the context was cancelled
+func (t *networkTester) cleanup() error {
+	if t.namespace == "" {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	return t.clients.Kube.CoreV1().Namespaces().Delete(ctx, t.namespace, metav1.DeleteOptions{})
+}
+
+// Security context accepted by the restricted pod security profile
+func restrictedSecurityContext() *corev1.SecurityContext {
+	noEscalation := false
+	nonRoot := true
+	return &corev1.SecurityContext{
+		AllowPrivilegeEscalation: &noEscalation,
+		RunAsNonRoot:             &nonRoot,
+		Capabilities:             &corev1.Capabilities{Drop: []corev1.Capability{"ALL"}},
+		SeccompProfile:           &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault},
+	}
+}
@@ -0,0 +1,144 @@
+/*
+Copyright © 2023 Givaldo Lins <gilins@redhat.com>
+*/
+package checks
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// Where the network probes run
+const (
+	ProbeEachNode = "node"
+	ProbeEachPool = "pool"
+)
+
+// Struct for a node where the probes run
+type probeNode struct {
+	name string
+	pool string
+}
+
+// Probe the network targets from every node or from a node of every pool
+func checkNodeProbes(ctx context.Context, clients *Clients, tester *networkTester, probeEach string, targets []NetworkTarget, script string) (Section, error) {
+	section := Section{Title: fmt.Sprintf("Checking network from every %s...", probeEach)}
+
+	nodes, err := selectProbeNodes(ctx, clients, tester.opts.NodeSelector, probeEach)
+	if err != nil {
+		return section, err
+	}
+
+	// Start all pods before waiting, so nodes are probed in parallel
+	names := []string{}
+	for i, node := range nodes {
+		name := fmt.Sprintf("node-probe-%d", i)
+		err = tester.start(ctx, name, node.name, script)
+		if err != nil {
+			return section, err
+		}
+		names = append(names, name)
+	}
+	finished, err := tester.wait(ctx, names...)
+	if err != nil {
+		return section, err
+	}
+
+	// Create a new table for the output, with a column for every target
+	header := []string{"NODE", "POOL"}
+	for _, target := range targets {
+		header = append(header, strings.ToUpper(target.Name))
+	}
+	table := newTable(header...)
+
+	warning := false
+	for i, node := range nodes {
+		row := []string{node.name, node.pool}
+		if !finished[names[i]] {
+			warning = true
+			for range targets {
+				row = append(row, "Not finished")
+			}
+			table.addRow(row...)
+			continue
+		}
+		logs, logsErr := tester.logs(ctx, names[i])
+		if logsErr != nil {
+			return section, logsErr
+		}
+		results, ok := targetsResults(logs, targets)
+		if !ok {
+			warning = true
+		}
+		table.addRow(append(row, results...)...)
+	}
+
+	// Set output
+	section.Status = warningStatus(warning)
+	section.Message = fmt.Sprintf("All network targets are reachable from %d node(s)", len(nodes))
+	if warning {
+		section.Message = "One or more network targets are not reachable from one or more nodes"
+	}
+	if tester.proxy != "" {
+		section.Message += fmt.Sprintf(", HTTP targets were tested through the cluster proxy %s", tester.proxy)
+	}
+	section.Table = table
+
+	return section, nil
+}
+
+// Select every node matching the node selector, or the first ready node of every machineconfigpool
+func selectProbeNodes(ctx context.Context, clients *Clients, nodeSelector map[string]string, probeEach string) ([]probeNode, error) {
+	nodes, err := clients.Kube.CoreV1().Nodes().List(ctx, metav1.ListOptions{LabelSelector: labels.SelectorFromSet(nodeSelector).String()})
+	if err != nil {
+		return nil, err
+	}
+
+	switch probeEach {
+	case ProbeEachNode:
+		selected := []probeNode{}
+		for _, node := range nodes.Items {
+			selected = append(selected, probeNode{name: node.Name})
+		}
+		return selected, nil
+	case ProbeEachPool:
+		pools, listErr := listMachineConfigPools(ctx, clients)
+		if listErr != nil {
+			return nil, listErr
+		}
+		selected := []probeNode{}
+		for _, mcp := range pools.Items {
+			if mcp.Spec == nil || mcp.Spec.NodeSelector == nil {
+				continue
+			}
+			selector, selectorErr := metav1.LabelSelectorAsSelector(mcp.Spec.NodeSelector)
+			if selectorErr != nil {
+				return nil, selectorErr
+			}
+			for _, node := range nodes.Items {
+				if selector.Matches(labels.Set(node.Labels)) && nodeReady(node) {
+					selected = append(selected, probeNode{name: node.Name, pool: mcp.Metadata.Name})
+					break
+				}
+			}
+		}
+		return selected, nil
+	default:
+		return nil, fmt.Errorf("invalid network probeEach %q, expected %s or %s", probeEach, ProbeEachNode, ProbeEachPool)
+	}
+}
+
+// Check if the node Ready condition is True
+func nodeReady(node corev1.Node) bool {
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == corev1.NodeReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
@@ -0,0 +1,97 @@
+/*
+Copyright © 2023 Givaldo Lins <gilins@redhat.com>
+*/
+package checks
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// NodeStatus checks the nodes taints and conditions
+func NodeStatus(ctx context.Context, clients *Clients, opts Options) (*Result, error) {
+	result := &Result{Title: "Checking nodes status..."}
+
+	// Get list of nodes
+	nodes, err := clients.Kube.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return result, err
+	}
+
+	result.add(checkTaints(nodes))
+	result.add(checkConditions(nodes))
+
+	return result, nil
+}
+
+// Check nodes conditions
+func checkConditions(nodes *corev1.NodeList) Section {
+
+	// Create a new table for the output
+	table := newTable("NAME", "MEMORY PRESSURE", "DISK PRESSURE", "PID PRESSURE", "READY")
+
+	warning := false
+	for _, node := range nodes.Items {
+		ready := affirmative
+		memory := negative
+		pid := negative
+		disk := negative
+		for _, condition := range node.Status.Conditions {
+			kind := condition.Type
+			status := condition.Status
+			if kind == "MemoryPressure" && status != negative {
+				memory = string(status)
+				warning = true
+			}
+			if kind == "DiskPressure" && status != negative {
+				disk = string(status)
+				warning = true
+			}
+			if kind == "PIDPressure" && status != negative {
+				pid = string(status)
+				warning = true
+			}
+			if kind == "Ready" && status != affirmative {
+				ready = string(status)
+				warning = true
+			}
+		}
+		table.addRow(node.Name, memory, disk, pid, ready)
+	}
+
+	// Set output
+	message := "All nodes are ready and healthy"
+	if warning {
+		message = "One or more node may need your attention"
+	}
+
+	return Section{Title: "Checking node conditions...", Status: warningStatus(warning), Message: message, Table: table}
+}
+
+// Check for taints
+func checkTaints(nodes *corev1.NodeList) Section {
+	// Create a new table for the output
+	table := newTable("NAME", "TAINT")
+
+	// Check taints
+	warning := false
+	for _, node := range nodes.Items {
+		for _, taint := range node.Spec.Taints {
+			if taint.Effect == "NoSchedule" && taint.Key == "node.kubernetes.io/unschedulable" {
+				warning = true
+			}
+			t := taint.Key + ": " + string(taint.Effect)
+			table.addRow(node.Name, t)
+		}
+	}
+
+	// Set output
+	message := "Some nodes are tainted and may need attention"
+	if warning {
+		message = "One or more nodes is tainted as NoSchedule"
+	}
+
+	return Section{Title: "Checking for node taints...", Status: warningStatus(warning), Message: message, Table: table}
+}
@@ -0,0 +1,133 @@
+/*
+Copyright © 2023 Givaldo Lins <gilins@redhat.com>
+*/
+package checks
+
+import (
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// Options used to tune the checks
+type Options struct {
+	// Show pods that has containers that restarted more times than this number
+	ContainerRestart int32
+	// Source used to get alerts: alertmanager (default) or thanos
+	AlertSource string
+	// Checks based on PromQL expressions
+	PromQLChecks []PromQLCheck
+	// Checks based on CEL expressions
+	CustomChecks []CustomCheck
+	// External check plugins
+	Plugins PluginOptions
+	// Update graph, lifecycle and history used by the version checks
+	Version VersionOptions
+	// Target release used by the upgrade readiness check
+	Upgrade UpgradeOptions
+	// Namespaces and expiry windows used by the certificate check
+	Certificates CertificateOptions
+	// Performance window and backup used by the etcd checks
+	Etcd EtcdOptions
+	// Tester pods used by the network checks
+	Network NetworkOptions
+	// Temporary route used by the ingress check
+	Ingress IngressOptions
+}
+
+// IngressOptions used by the ingress check
+type IngressOptions struct {
+	// Create a temporary route and check that it is admitted and reachable
+	TestRoute bool
+}
+
+// NetworkOptions used to run the network tester pods
+type NetworkOptions struct {
+	// Image of the tester pods, it needs bash, curl and dig (default is network-tools-rhel8)
+	Image string
+	// Pull secret copied to the temporary namespace, informed as namespace/name
+	PullSecret string
+	// Node selector and tolerations of the tester pods
+	NodeSelector map[string]string
+	Tolerations  []corev1.Toleration
+	// Maximum time for each tester pod to finish (default 2 minutes)
+	Timeout time.Duration
+	// Run the probes from every node or from a node of every machineconfigpool, empty runs a single pod
+	ProbeEach string
+	// Destinations probed by the tester pods (default is external and internal DNS, egress and the API service)
+	Targets []NetworkTarget
+	// Test pod-to-pod, pod-to-service and pod-to-node connectivity between nodes with a DaemonSet
+	Matrix bool
+	// Maximum number of nodes in the connectivity matrix, nodes are sampled above it (default 10)
+	MatrixMaxNodes int
+}
+
+// EtcdOptions used by the etcd performance and backup checks
+type EtcdOptions struct {
+	// Window over which the etcd metrics are evaluated (default 5 minutes)
+	Window time.Duration
+	// CronJob that backs up etcd and its namespace, the backup is not checked when any is empty
+	BackupCronJob   string
+	BackupNamespace string
+	// Maximum age of the last successful backup (default 24 hours)
+	BackupMaxAge time.Duration
+}
+
+// CertificateOptions used by the certificate check
+type CertificateOptions struct {
+	// Check TLS secrets in all namespaces instead of only openshift-* ones
+	AllNamespaces bool
+	// Report certificates expiring within this number of days (default 30)
+	WarningDays int
+	// Flag certificates expiring within this number of days as critical (default 7)
+	CriticalDays int
+}
+
+// Validate the expiry windows, the critical one must be shorter than the warning one
+func (o CertificateOptions) Validate() error {
+	warning, critical := o.WarningDays, o.CriticalDays
+	if warning <= 0 {
+		warning = defaultCertWarningDays
+	}
+	if critical <= 0 {
+		critical = defaultCertCriticalDays
+	}
+	if critical >= warning {
+		return fmt.Errorf("certificates criticalDays (%d) must be lower than warningDays (%d)", critical, warning)
+	}
+	return nil
+}
+
+// UpgradeOptions used by the upgrade readiness check
+type UpgradeOptions struct {
+	// Target minor release, like 4.14
+	Target string
+}
+
+// VersionOptions used to find the latest release and the support lifecycle
+type VersionOptions struct {
+	// Update graph URL, like a local OpenShift Update Service (default is the cluster upstream or api.openshift.com)
+	GraphURL string
+	// Update graph saved to a file, used instead of the URL on disconnected environments
+	GraphFile string
+	// JSON file overriding the embedded lifecycle table
+	LifecycleFile string
+	// Number of update history entries listed (default 5)
+	HistoryLimit int
+}
+
+// PluginOptions used to discover and run the external check plugins
+type PluginOptions struct {
+	// Directory set in the config file, searched first and reported when it can not be read
+	Dir string
+	// Directories searched for plugins after Dir, missing ones are ignored
+	Dirs []string
+	// Maximum time for each plugin to run (default 1 minute)
+	Timeout time.Duration
+	// Kubeconfig passed to the plugins
+	Kubeconfig string
+	// Version and debug mode passed to the plugins in the context
+	Version string
+	Debug   bool
+}
@@ -0,0 +1,64 @@
+/*
+Copyright © 2023 Givaldo Lins <gilins@redhat.com>
+*/
+package checks
+
+import (
+	"context"
+	"fmt"
+
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// PDBStatus checks for restrictive PDBs
+func PDBStatus(ctx context.Context, clients *Clients, opts Options) (*Result, error) {
+	result := &Result{Title: "Checking PDBs status..."}
+
+	// Get a list of pdbs
+	pdbs, err := clients.Kube.PolicyV1().PodDisruptionBudgets("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return result, err
+	}
+
+	// Create a new table for the output
+	table := newTable("PDB NAME", "NAMESPACE", "REASON")
+
+	// List PDBs that do not allow any disruption
+	for _, pdb := range pdbs.Items {
+		if reason := restrictivePDB(pdb); reason != "" {
+			table.addRow(pdb.Name, pdb.Namespace, reason)
+		}
+	}
+
+	// Set output
+	if len(table.Rows) > 0 {
+		result.add(Section{Status: StatusWarning, Message: "There is one or more restrictive PDBs that may cause node drain failure", Table: table})
+	} else {
+		result.add(Section{Status: StatusInfo, Message: "There is no restrictive PDB"})
+	}
+
+	return result, nil
+}
+
+// Get the reason why a PDB blocks node drains, or an empty string when it does not
+func restrictivePDB(pdb policyv1.PodDisruptionBudget) string {
+	maxUnavail := pdb.Spec.MaxUnavailable
+	if maxUnavail != nil {
+		if (maxUnavail.StrVal == "" && maxUnavail.IntVal == 0) || maxUnavail.StrVal == "0%" {
+			return fmt.Sprintf("maxUnavailable is %s", maxUnavail.String())
+		}
+	}
+
+	minAvail := pdb.Spec.MinAvailable
+	if minAvail != nil && minAvail.StrVal == "100%" {
+		return "minAvailable is 100%"
+	}
+
+	// Pods are covered but none of them can be evicted right now
+	if pdb.Status.ExpectedPods > 0 && pdb.Status.DisruptionsAllowed == 0 {
+		return fmt.Sprintf("no disruption allowed, %d of %d pods healthy", pdb.Status.CurrentHealthy, pdb.Status.ExpectedPods)
+	}
+
+	return ""
+}
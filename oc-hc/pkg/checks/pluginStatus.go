@@ -0,0 +1,181 @@
+/*
+Copyright © 2023 Givaldo Lins <gilins@redhat.com>
+*/
+package checks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// PluginPrefix is the prefix used to discover plugins
+const PluginPrefix = "oc-hc-check-"
+
+// Struct sent to the plugins on stdin
+type pluginContext struct {
+	Kubeconfig string `json:"kubeconfig"`
+	Version    string `json:"version"`
+	Debug      bool   `json:"debug"`
+}
+
+// Structs expected from the plugins on stdout
+type pluginResponse struct {
+	Name     string          `json:"name"`
+	Findings []pluginFinding `json:"findings"`
+}
+type pluginFinding struct {
+	Severity string `json:"severity"`
+	Object   string `json:"object"`
+	Message  string `json:"message"`
+}
+
+// PluginStatus runs the external check plugins, it returns a nil Result when there is no plugin to run
+func PluginStatus(ctx context.Context, clients *Clients, opts Options) (*Result, error) {
+	plugins, dirErr := findPlugins(opts.Plugins.Dir, opts.Plugins.Dirs)
+	if len(plugins) == 0 && dirErr == nil {
+		return nil, nil
+	}
+
+	result := &Result{Title: "Checking plugins..."}
+	if dirErr != nil {
+		result.add(Section{Status: StatusError, Message: fmt.Sprintf("Plugins dir %s can not be read: %s", opts.Plugins.Dir, dirErr)})
+	}
+
+	timeout := opts.Plugins.Timeout
+	if timeout <= 0 {
+		timeout = time.Minute
+	}
+
+	input, err := json.Marshal(pluginContext{Kubeconfig: opts.Plugins.Kubeconfig, Version: opts.Plugins.Version, Debug: opts.Plugins.Debug})
+	if err != nil {
+		return result, err
+	}
+
+	for _, plugin := range plugins {
+		name := strings.TrimPrefix(filepath.Base(plugin), PluginPrefix)
+		title := fmt.Sprintf("Checking %s...", name)
+
+		response, runErr := runPlugin(ctx, plugin, opts.Plugins.Kubeconfig, input, timeout)
+		if runErr != nil {
+			result.add(Section{Title: title, Status: StatusError, Message: fmt.Sprintf("Plugin %s failed: %s", plugin, runErr)})
+			continue
+		}
+		result.add(pluginSection(title, response))
+	}
+
+	return result, nil
+}
+
+// Find the executables named oc-hc-check-* in the configured dir and the given directories
+func findPlugins(configured string, dirs []string) ([]string, error) {
+	var dirErr error
+	if configured != "" {
+		dirs = append([]string{configured}, dirs...)
+	}
+
+	seen := map[string]bool{}
+	plugins := []string{}
+	for i, dir := range dirs {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			// Only the configured dir is expected to exist
+			if i == 0 && configured != "" {
+				dirErr = err
+			}
+			continue
+		}
+		for _, entry := range entries {
+			name := entry.Name()
+			if !strings.HasPrefix(name, PluginPrefix) || seen[name] {
+				continue
+			}
+			info, infoErr := entry.Info()
+			if infoErr != nil || info.IsDir() || info.Mode()&0o111 == 0 {
+				continue
+			}
+			seen[name] = true
+			plugins = append(plugins, filepath.Join(dir, name))
+		}
+	}
+	sort.Slice(plugins, func(i, j int) bool {
+		return filepath.Base(plugins[i]) < filepath.Base(plugins[j])
+	})
+
+	return plugins, dirErr
+}
+
+// Run a plugin and decode its findings
+func runPlugin(ctx context.Context, plugin string, kubeconfig string, input []byte, timeout time.Duration) (*pluginResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, plugin, "--kubeconfig", kubeconfig) //nolint:gosec
+	cmd.Env = append(os.Environ(), "KUBECONFIG="+kubeconfig)
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	// Do not wait forever for children of the plugin that keep stdout open
+	cmd.WaitDelay = time.Second
+
+	err := cmd.Run()
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return nil, fmt.Errorf("timed out after %s", timeout)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	response := pluginResponse{}
+	err = json.Unmarshal(stdout.Bytes(), &response)
+	if err != nil {
+		return nil, fmt.Errorf("invalid output, expected findings json: %w", err)
+	}
+	for _, finding := range response.Findings {
+		if finding.Severity != SeverityCritical && finding.Severity != SeverityWarning && finding.Severity != SeverityInfo {
+			return nil, fmt.Errorf("invalid severity %q in finding %q", finding.Severity, finding.Message)
+		}
+	}
+	if response.Name == "" {
+		response.Name = strings.TrimPrefix(filepath.Base(plugin), PluginPrefix)
+	}
+
+	return &response, nil
+}
+
+// Convert the findings returned by a plugin into a section
+func pluginSection(title string, response *pluginResponse) Section {
+	// Create a new table for the output
+	table := newTable("SEVERITY", "OBJECT", "MESSAGE")
+
+	warning := false
+	for _, finding := range response.Findings {
+		if finding.Severity != SeverityInfo {
+			warning = true
+		}
+		table.addRow(finding.Severity, finding.Object, finding.Message)
+	}
+
+	// Set output
+	section := Section{Title: title, Status: warningStatus(warning), Table: table}
+	switch {
+	case warning:
+		section.Message = fmt.Sprintf("%s returned one or more findings that may require your attention", response.Name)
+	case len(response.Findings) > 0:
+		section.Message = fmt.Sprintf("%s returned informational findings only", response.Name)
+	default:
+		section.Message = fmt.Sprintf("%s did not return any finding", response.Name)
+		section.Table = nil
+	}
+
+	return section
+}
@@ -0,0 +1,87 @@
+/*
+Copyright © 2023 Givaldo Lins <gilins@redhat.com>
+*/
+package checks
+
+import (
+	"context"
+	"fmt"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// PodStatus checks for pods restarts and failed pods
+func PodStatus(ctx context.Context, clients *Clients, opts Options) (*Result, error) {
+	result := &Result{Title: "Checking pods status..."}
+
+	// Get a list of pods
+	pods, err := clients.Kube.CoreV1().Pods("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return result, err
+	}
+
+	result.add(podRestart(pods, opts.ContainerRestart))
+
+	result.add(failedPods(pods))
+
+	return result, nil
+}
+
+// Check for pods restarts
+func podRestart(pods *v1.PodList, restartNumber int32) Section {
+	section := Section{Title: "Checking for pods restart..."}
+
+	// Create a new table for the output
+	table := newTable("POD NAME", "CONTAINER NAME", "NAMESPACE", "RESTARTS")
+
+	// List pods that have restarted more than a given number
+	warning := false
+	for _, pod := range pods.Items {
+		for _, container := range pod.Status.ContainerStatuses {
+			if container.RestartCount > restartNumber {
+				warning = true
+				table.addRow(pod.Name, container.Name, pod.Namespace, fmt.Sprint(container.RestartCount))
+			}
+		}
+	}
+
+	// Set output
+	if warning {
+		section.Status = StatusWarning
+		section.Message = fmt.Sprintf("There is one or more pods that restarted more than %d times", restartNumber)
+		section.Table = table
+	} else {
+		section.Status = StatusInfo
+		section.Message = fmt.Sprintf("There is no pod that restarted more than %d", restartNumber)
+	}
+	return section
+}
+
+func failedPods(pods *v1.PodList) Section {
+	section := Section{Title: "Checking for failed pods..."}
+
+	// Create a new table for the output
+	table := newTable("POD NAME", "NAMESPACE", "STATUS")
+
+	// Check pods
+	warning := false
+	for _, pod := range pods.Items {
+		// List pods that are not running or succeeded
+		if pod.Status.Phase != "Running" && pod.Status.Phase != "Succeeded" {
+			warning = true
+			table.addRow(pod.Name, pod.Namespace, string(pod.Status.Phase))
+		}
+	}
+
+	// Set output
+	if warning {
+		section.Status = StatusWarning
+		section.Message = "There is one or more pods in failed state"
+		section.Table = table
+	} else {
+		section.Status = StatusInfo
+		section.Message = "There is no pod in failed state"
+	}
+	return section
+}
@@ -0,0 +1,215 @@
+/*
+Copyright © 2023 Givaldo Lins <gilins@redhat.com>
+*/
+package checks
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+// Severities accepted by the PromQL checks, custom checks and plugins
+const (
+	SeverityCritical = "critical"
+	SeverityWarning  = "warning"
+	SeverityInfo     = "info"
+)
+
+// PromQLCheck is a check based on a PromQL expression
+type PromQLCheck struct {
+	Name      string  `mapstructure:"name"`
+	Query     string  `mapstructure:"query"`
+	Operator  string  `mapstructure:"operator"`
+	Threshold float64 `mapstructure:"threshold"`
+	Severity  string  `mapstructure:"severity"`
+	Message   string  `mapstructure:"message"`
+}
+
+// Struct for the Prometheus query endpoint
+type promQueryResponse struct {
+	Status string `json:"status"`
+	Error  string `json:"error"`
+	Data   struct {
+		ResultType string `json:"resultType"`
+		Result     []struct {
+			Metric map[string]string `json:"metric"`
+			Value  []interface{}     `json:"value"`
+		} `json:"result"`
+	} `json:"data"`
+}
+
+// Struct for a single sample returned by a query
+type promSample struct {
+	Labels map[string]string
+	Value  float64
+}
+
+// Struct used to render the message template of a PromQL check
+type promqlMessageData struct {
+	Name      string
+	Labels    map[string]string
+	Value     float64
+	Operator  string
+	Threshold float64
+}
+
+// PromQLStatus runs the PromQL checks, it returns a nil Result when there is no check to run
+func PromQLStatus(ctx context.Context, clients *Clients, opts Options) (*Result, error) {
+	if len(opts.PromQLChecks) == 0 {
+		return nil, nil
+	}
+
+	result := &Result{Title: "Checking PromQL rules..."}
+
+	// Get Thanos Querier route and user token
+	host, err := getRouteHost(ctx, clients, "openshift-monitoring", "thanos-querier")
+	if err != nil {
+		return result, err
+	}
+	bearerToken, err := getBearerToken(clients.RestConfig)
+	if err != nil {
+		return result, err
+	}
+
+	for _, check := range opts.PromQLChecks {
+		section, checkErr := runPromqlCheck(ctx, host, bearerToken, check)
+		if checkErr != nil {
+			result.add(Section{Title: section.Title, Status: StatusError, Message: fmt.Sprintf("Check %s failed: %s", check.Name, checkErr)})
+			continue
+		}
+		result.add(section)
+	}
+
+	return result, nil
+}
+
+// Run a single PromQL check
+func runPromqlCheck(ctx context.Context, host string, bearerToken string, check PromQLCheck) (Section, error) {
+	section := Section{Title: fmt.Sprintf("Checking %s...", check.Name)}
+
+	// Validate check definition
+	if check.Severity == "" {
+		check.Severity = SeverityWarning
+	}
+	if check.Severity != SeverityCritical && check.Severity != SeverityWarning && check.Severity != SeverityInfo {
+		return section, fmt.Errorf("invalid severity %q for check %q", check.Severity, check.Name)
+	}
+	if _, opErr := compareThreshold(0, check.Operator, check.Threshold); opErr != nil {
+		return section, opErr
+	}
+	if check.Message == "" {
+		check.Message = "{{ .Name }} is {{ .Value }}, expected to not be {{ .Operator }} {{ .Threshold }}"
+	}
+	msgTemplate, err := template.New(check.Name).Parse(check.Message)
+	if err != nil {
+		return section, err
+	}
+
+	samples, err := queryPrometheus(ctx, host, bearerToken, check.Query)
+	if err != nil {
+		return section, err
+	}
+
+	// Create a new table for the output
+	table := newTable("SEVERITY", "VALUE", "MESSAGE")
+
+	// Compare every sample against the threshold
+	warning := false
+	for _, sample := range samples {
+		breached, _ := compareThreshold(sample.Value, check.Operator, check.Threshold)
+		if !breached {
+			continue
+		}
+
+		var message strings.Builder
+		err = msgTemplate.Execute(&message, promqlMessageData{Name: check.Name, Labels: sample.Labels, Value: sample.Value, Operator: check.Operator, Threshold: check.Threshold})
+		if err != nil {
+			return section, err
+		}
+		warning = true
+		table.addRow(check.Severity, strconv.FormatFloat(sample.Value, 'g', 6, 64), message.String())
+	}
+
+	// Set output
+	if !warning {
+		section.Status = StatusInfo
+		section.Message = fmt.Sprintf("%s is within the expected threshold", check.Name)
+		return section, nil
+	}
+	section.Status = severityStatus(check.Severity)
+	section.Message = fmt.Sprintf("%s returned one or more series %s %v", check.Name, check.Operator, check.Threshold)
+	section.Table = table
+
+	return section, nil
+}
+
+// Run an instant query against Prometheus/Thanos Querier
+func queryPrometheus(ctx context.Context, host string, bearerToken string, query string) ([]promSample, error) {
+	var response promQueryResponse
+	err := getJSON(ctx, "https://"+host+"/api/v1/query?query="+url.QueryEscape(query), bearerToken, &response)
+	if err != nil {
+		return nil, err
+	}
+	if response.Status != "success" {
+		return nil, fmt.Errorf("query %q failed: %s", query, response.Error)
+	}
+	if response.Data.ResultType != "vector" {
+		return nil, fmt.Errorf("query %q returned a %s, only instant vectors are supported", query, response.Data.ResultType)
+	}
+
+	samples := []promSample{}
+	for _, result := range response.Data.Result {
+		if len(result.Value) != 2 {
+			continue
+		}
+		raw, ok := result.Value[1].(string)
+		if !ok {
+			continue
+		}
+		value, parseErr := strconv.ParseFloat(raw, 64)
+		if parseErr != nil {
+			return nil, parseErr
+		}
+		samples = append(samples, promSample{Labels: result.Metric, Value: value})
+	}
+
+	// Keep output stable between runs
+	sort.SliceStable(samples, func(i, j int) bool {
+		return fmt.Sprint(samples[i].Labels) < fmt.Sprint(samples[j].Labels)
+	})
+
+	return samples, nil
+}
+
+// Compare a value against a threshold using the given operator
+func compareThreshold(value float64, operator string, threshold float64) (bool, error) {
+	switch operator {
+	case ">":
+		return value > threshold, nil
+	case ">=":
+		return value >= threshold, nil
+	case "<":
+		return value < threshold, nil
+	case "<=":
+		return value <= threshold, nil
+	case "==":
+		return value == threshold, nil
+	case "!=":
+		return value != threshold, nil
+	default:
+		return false, fmt.Errorf("invalid operator %q", operator)
+	}
+}
+
+// Map a severity to the status of a section
+func severityStatus(severity string) Status {
+	if severity == SeverityInfo {
+		return StatusInfo
+	}
+	return StatusWarning
+}
@@ -0,0 +1,80 @@
+/*
+Copyright © 2023 Givaldo Lins <gilins@redhat.com>
+*/
+
+// Package checks implements the OpenShift cluster health checks used by oc-hc.
+//
+// Every check takes the clients and options and returns a Result instead of
+// printing it, so the checks can be embedded in other tools. When a check
+// fails, the Result returned with the error holds the sections completed
+// before the failure.
+package checks
+
+import "context"
+
+// Status of a section of a check
+type Status string
+
+const (
+	StatusInfo    Status = "Info"
+	StatusWarning Status = "Warning"
+	StatusError   Status = "Error"
+	// StatusSkipped is used when a check depends on something that is not available
+	StatusSkipped Status = "Skipped"
+)
+
+// Check is the signature shared by all checks
+type Check func(ctx context.Context, clients *Clients, opts Options) (*Result, error)
+
+// Result holds the outcome of a check
+type Result struct {
+	Title    string    `json:"title"`
+	Sections []Section `json:"sections"`
+}
+
+// Section is a group of findings inside a check
+type Section struct {
+	Title   string `json:"title,omitempty"`
+	Status  Status `json:"status"`
+	Message string `json:"message"`
+	Table   *Table `json:"table,omitempty"`
+}
+
+// Table holds the details of a section
+type Table struct {
+	Header []string   `json:"header"`
+	Rows   [][]string `json:"rows"`
+}
+
+// Healthy reports if no section of the result has a warning or an error
+func (r *Result) Healthy() bool {
+	for _, section := range r.Sections {
+		if section.Status == StatusWarning || section.Status == StatusError {
+			return false
+		}
+	}
+	return true
+}
+
+// Create a new table with the given header
+func newTable(header ...string) *Table {
+	return &Table{Header: header, Rows: [][]string{}}
+}
+
+// Add a row to the table
+func (t *Table) addRow(row ...string) {
+	t.Rows = append(t.Rows, row)
+}
+
+// Add a section to the result
+func (r *Result) add(section Section) {
+	r.Sections = append(r.Sections, section)
+}
+
+// Pick the status according to the warning flag
+func warningStatus(warning bool) Status {
+	if warning {
+		return StatusWarning
+	}
+	return StatusInfo
+}
@@ -0,0 +1,140 @@
+/*
+Copyright © 2023 Givaldo Lins <gilins@redhat.com>
+*/
+package checks
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	routev1 "github.com/openshift/api/route/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// RouteStatus checks the routes admission, backends and certificates across namespaces
+func RouteStatus(ctx context.Context, clients *Clients, opts Options) (*Result, error) {
+	result := &Result{Title: "Checking routes..."}
+
+	routes, err := clients.Route.RouteV1().Routes("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return result, err
+	}
+
+	result.add(routeAdmission(routes.Items))
+
+	section, err := routeBackends(ctx, clients, routes.Items)
+	if err != nil {
+		return result, err
+	}
+	result.add(section)
+
+	result.add(routeCertificates(routes.Items, newCertWindows(opts.Certificates, time.Now())))
+
+	return result, nil
+}
+
+// Check for routes not admitted by a router
+func routeAdmission(routes []routev1.Route) Section {
+	section := Section{Title: "Checking routes admission..."}
+
+	// Create a new table for the output
+	table := newTable("NAMESPACE", "ROUTE", "HOST", "ROUTER", "REASON", "MESSAGE")
+
+	for _, route := range routes {
+		for _, ingress := range route.Status.Ingress {
+			for _, condition := range ingress.Conditions {
+				if condition.Type == routev1.RouteAdmitted && condition.Status == corev1.ConditionFalse {
+					table.addRow(route.Namespace, route.Name, route.Spec.Host, ingress.RouterName, condition.Reason, truncate(condition.Message, 60))
+				}
+			}
+		}
+	}
+
+	// Set output
+	section.Status = warningStatus(len(table.Rows) > 0)
+	section.Message = "All routes are admitted"
+	if len(table.Rows) > 0 {
+		section.Message = "There is one or more routes not admitted by a router"
+		section.Table = table
+	}
+
+	return section
+}
+
+// Check for routes pointing to services without ready endpoints
+func routeBackends(ctx context.Context, clients *Clients, routes []routev1.Route) (Section, error) {
+	section := Section{Title: "Checking routes backends..."}
+
+	services, err := clients.Kube.CoreV1().Services("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return section, err
+	}
+	existing := map[string]bool{}
+	externalName := map[string]bool{}
+	for _, service := range services.Items {
+		existing[service.Namespace+"/"+service.Name] = true
+		externalName[service.Namespace+"/"+service.Name] = service.Spec.Type == corev1.ServiceTypeExternalName
+	}
+	ready, err := readyEndpoints(ctx, clients)
+	if err != nil {
+		return section, err
+	}
+
+	// Create a new table for the output
+	table := newTable("NAMESPACE", "ROUTE", "SERVICE", "REASON")
+
+	for _, route := range routes {
+		backends := append([]routev1.RouteTargetReference{route.Spec.To}, route.Spec.AlternateBackends...)
+		for _, backend := range backends {
+			if backend.Kind != "Service" {
+				continue
+			}
+			key := route.Namespace + "/" + backend.Name
+			switch {
+			case externalName[key]:
+				// ExternalName services do not have endpoints to check
+			case !existing[key]:
+				table.addRow(route.Namespace, route.Name, backend.Name, "Service not found")
+			case ready[key] == 0:
+				table.addRow(route.Namespace, route.Name, backend.Name, "Service has no ready endpoints")
+			}
+		}
+	}
+
+	// Set output
+	section.Status = warningStatus(len(table.Rows) > 0)
+	section.Message = "All routes point to services with ready endpoints"
+	if len(table.Rows) > 0 {
+		section.Message = "There is one or more routes pointing to services without ready endpoints"
+		section.Table = table
+	}
+
+	return section, nil
+}
+
+// Check the certificates embedded in edge and reencrypt routes
+func routeCertificates(routes []routev1.Route, windows certWindows) Section {
+	section := Section{Title: "Checking routes certificates..."}
+
+	// Create a new table for the output
+	table := newCertTable()
+
+	checked := 0
+	for _, route := range routes {
+		if route.Spec.TLS == nil || route.Spec.TLS.Certificate == "" {
+			continue
+		}
+		if route.Spec.TLS.Termination != routev1.TLSTerminationEdge && route.Spec.TLS.Termination != routev1.TLSTerminationReencrypt {
+			continue
+		}
+		checked++
+		addCertRows(table, fmt.Sprintf("route/%s/%s", route.Namespace, route.Name), []byte(route.Spec.TLS.Certificate), windows)
+	}
+
+	// Set output
+	setCertOutput(&section, table, fmt.Sprintf("All certificates in %d edge or reencrypt route(s) are valid for more than %d days", checked, windows.warningDays()))
+
+	return section
+}
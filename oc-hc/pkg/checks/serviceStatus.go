@@ -0,0 +1,109 @@
+/*
+Copyright © 2023 Givaldo Lins <gilins@redhat.com>
+*/
+package checks
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// ServiceStatus checks for services without ready endpoints
+func ServiceStatus(ctx context.Context, clients *Clients, opts Options) (*Result, error) {
+	result := &Result{Title: "Checking services endpoints..."}
+
+	services, err := clients.Kube.CoreV1().Services("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return result, err
+	}
+	ready, err := readyEndpoints(ctx, clients)
+	if err != nil {
+		return result, err
+	}
+	pods, err := clients.Kube.CoreV1().Pods("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return result, err
+	}
+
+	// Create a new table for the output
+	table := newTable("NAMESPACE", "SERVICE", "SELECTOR", "MATCHING PODS NOT READY")
+
+	for _, service := range services.Items {
+		// Headless and ExternalName services do not have endpoints to check
+		if service.Spec.Type == corev1.ServiceTypeExternalName || service.Spec.ClusterIP == corev1.ClusterIPNone {
+			continue
+		}
+		if ready[service.Namespace+"/"+service.Name] > 0 {
+			continue
+		}
+
+		selector := "<none>"
+		notReady := "<none>"
+		if len(service.Spec.Selector) > 0 {
+			selector = labels.SelectorFromSet(service.Spec.Selector).String()
+			notReady = strings.Join(matchingPodsNotReady(pods.Items, service), ",")
+			if notReady == "" {
+				notReady = "<no matching pod>"
+			}
+		}
+		table.addRow(service.Namespace, service.Name, selector, truncate(notReady, 80))
+	}
+
+	// Set output
+	if len(table.Rows) > 0 {
+		result.add(Section{Status: StatusWarning, Message: "There is one or more services without ready endpoints", Table: table})
+	} else {
+		result.add(Section{Status: StatusInfo, Message: "All services have ready endpoints"})
+	}
+
+	return result, nil
+}
+
+// List the pods matched by the service selector that are not ready
+func matchingPodsNotReady(pods []corev1.Pod, service corev1.Service) []string {
+	selector := labels.SelectorFromSet(service.Spec.Selector)
+
+	names := []string{}
+	for _, pod := range pods {
+		if pod.Namespace != service.Namespace || !selector.Matches(labels.Set(pod.Labels)) {
+			continue
+		}
+		if !podReady(pod) {
+			names = append(names, fmt.Sprintf("%s(%s)", pod.Name, pod.Status.Phase))
+		}
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// Count the ready endpoints of every service from its endpointslices, keyed by namespace/name
+func readyEndpoints(ctx context.Context, clients *Clients) (map[string]int, error) {
+	slices, err := clients.Kube.DiscoveryV1().EndpointSlices("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	ready := map[string]int{}
+	for _, slice := range slices.Items {
+		service := slice.Labels[discoveryv1.LabelServiceName]
+		if service == "" {
+			continue
+		}
+		for _, endpoint := range slice.Endpoints {
+			// A nil ready condition must be interpreted as ready
+			if endpoint.Conditions.Ready == nil || *endpoint.Conditions.Ready {
+				ready[slice.Namespace+"/"+service] += len(endpoint.Addresses)
+			}
+		}
+	}
+
+	return ready, nil
+}
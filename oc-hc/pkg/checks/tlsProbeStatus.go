@@ -0,0 +1,209 @@
+/*
+Copyright © 2023 Givaldo Lins <gilins@redhat.com>
+*/
+package checks
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Handshakes slower than this are reported
+const slowHandshake = time.Second
+
+// Struct for an endpoint probed by the TLS check, managed ones serve a certificate rotated by an operator
+type tlsEndpoint struct {
+	name    string
+	host    string
+	port    string
+	managed bool
+}
+
+// TLSProbeStatus dials the external cluster endpoints and checks the certificates they serve
+func TLSProbeStatus(ctx context.Context, clients *Clients, opts Options) (*Result, error) {
+	result := &Result{Title: "Checking TLS on external endpoints..."}
+
+	endpoints, err := externalEndpoints(ctx, clients)
+	if err != nil {
+		return result, err
+	}
+	roots, err := clusterCertPool(ctx, clients)
+	if err != nil {
+		return result, err
+	}
+
+	windows := newCertWindows(opts.Certificates, time.Now())
+
+	// Create a new table for the output
+	table := newTable("ENDPOINT", "ADDRESS", "CHAIN", "HOSTNAME", "EXPIRES IN", "LATENCY")
+
+	warning := false
+	critical := false
+	for _, endpoint := range endpoints {
+		row, ok := probeTLS(ctx, endpoint, roots, windows)
+		if !ok {
+			warning = true
+		}
+		if expires := row[4]; strings.HasPrefix(expires, certExpired) || strings.HasPrefix(expires, certCritical) {
+			critical = true
+		}
+		table.addRow(row...)
+	}
+
+	// Set output
+	section := Section{Status: warningStatus(warning), Message: "All external endpoints serve valid certificates", Table: table}
+	if warning {
+		section.Message = "One or more external endpoints may require your attention"
+	}
+	if critical {
+		section.Status = StatusError
+	}
+	result.add(section)
+
+	return result, nil
+}
+
+// Find the API URL, the console route and a hostname under the default ingress wildcard
+func externalEndpoints(ctx context.Context, clients *Clients) ([]tlsEndpoint, error) {
+	endpoints := []tlsEndpoint{}
+
+	// Hosts without a scheme are valid in a kubeconfig and default to https
+	host := clients.RestConfig.Host
+	if !strings.Contains(host, "://") {
+		host = "https://" + host
+	}
+	api, err := url.Parse(host)
+	if err != nil {
+		return nil, err
+	}
+	port := api.Port()
+	if port == "" {
+		port = "443"
+	}
+	// The API serving certificate is rotated by the kube-apiserver operator unless a named certificate covers the host
+	apiserver, err := clients.Config.ConfigV1().APIServers().Get(ctx, "cluster", metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	managed := true
+	for _, named := range apiserver.Spec.ServingCerts.NamedCertificates {
+		for _, name := range named.Names {
+			if name == api.Hostname() {
+				managed = false
+			}
+		}
+	}
+	endpoints = append(endpoints, tlsEndpoint{"API server", api.Hostname(), port, managed})
+
+	console, err := getRouteHost(ctx, clients, "openshift-console", "console")
+	if err != nil && !apierrors.IsNotFound(err) {
+		return nil, err
+	}
+	if err == nil {
+		endpoints = append(endpoints, tlsEndpoint{"Console", console, "443", false})
+	}
+
+	// Any hostname under the ingress domain is served by the default router
+	ingress, err := clients.Config.ConfigV1().Ingresses().Get(ctx, "cluster", metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	if ingress.Spec.Domain != "" {
+		endpoints = append(endpoints, tlsEndpoint{"Ingress wildcard", "oc-hc-tls-probe." + ingress.Spec.Domain, "443", false})
+	}
+
+	return endpoints, nil
+}
+
+// Build a pool with the system CAs, the kubeconfig CA and the default ingress CA
+func clusterCertPool(ctx context.Context, clients *Clients) (*x509.CertPool, error) {
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		pool = x509.NewCertPool()
+	}
+
+	tlsConfig := clients.RestConfig.TLSClientConfig
+	if len(tlsConfig.CAData) > 0 {
+		pool.AppendCertsFromPEM(tlsConfig.CAData)
+	}
+	if tlsConfig.CAFile != "" {
+		data, readErr := os.ReadFile(tlsConfig.CAFile)
+		if readErr != nil {
+			return nil, readErr
+		}
+		pool.AppendCertsFromPEM(data)
+	}
+
+	configmap, err := clients.Kube.CoreV1().ConfigMaps("openshift-config-managed").Get(ctx, "default-ingress-cert", metav1.GetOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return nil, err
+	}
+	if err == nil {
+		pool.AppendCertsFromPEM([]byte(configmap.Data["ca-bundle.crt"]))
+	}
+
+	return pool, nil
+}
+
+// Complete a TLS handshake with the endpoint and verify the certificate it serves
+func probeTLS(ctx context.Context, endpoint tlsEndpoint, roots *x509.CertPool, windows certWindows) ([]string, bool) {
+	address := net.JoinHostPort(endpoint.host, endpoint.port)
+
+	// The chain is verified below to report every problem instead of failing the handshake
+	dialer := &tls.Dialer{
+		NetDialer: &net.Dialer{Timeout: 10 * time.Second},
+		Config:    &tls.Config{ServerName: endpoint.host, InsecureSkipVerify: true}, //nolint:gosec
+	}
+	start := time.Now()
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return []string{endpoint.name, address, "Handshake failed: " + truncate(err.Error(), 60), "", "", ""}, false
+	}
+	latency := time.Since(start)
+	state := conn.(*tls.Conn).ConnectionState()
+	conn.Close()
+
+	ok := true
+	leaf := state.PeerCertificates[0]
+	intermediates := x509.NewCertPool()
+	for _, cert := range state.PeerCertificates[1:] {
+		intermediates.AddCert(cert)
+	}
+
+	chain := "Valid"
+	if _, verifyErr := leaf.Verify(x509.VerifyOptions{Roots: roots, Intermediates: intermediates, CurrentTime: windows.now}); verifyErr != nil {
+		chain = truncate(verifyErr.Error(), 60)
+		ok = false
+	}
+
+	hostname := "Match"
+	if hostErr := leaf.VerifyHostname(endpoint.host); hostErr != nil {
+		hostname = "Mismatch"
+		ok = false
+	}
+
+	// Same states as the certificate check, operator rotated certificates are judged by their lifetime left
+	windows.managed = endpoint.managed
+	expires := windows.state(leaf)
+	if expires != "" {
+		ok = false
+	} else {
+		expires = fmt.Sprintf("%d day(s)", int(leaf.NotAfter.Sub(windows.now).Hours()/24))
+	}
+
+	if latency > slowHandshake {
+		ok = false
+	}
+
+	return []string{endpoint.name, address, chain, hostname, expires, latency.Round(time.Millisecond).String()}, ok
+}
@@ -0,0 +1,293 @@
+/*
+Copyright © 2023 Givaldo Lins <gilins@redhat.com>
+*/
+package checks
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	configv1 "github.com/openshift/api/config/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// GroupVersionResource for OLM clusterServiceVersions
+var csvResource = schema.GroupVersionResource{Group: "operators.coreos.com", Version: "v1alpha1", Resource: "clusterserviceversions"}
+
+// Impact of an upgrade readiness item
+const (
+	impactBlocking = "Blocking"
+	impactAdvisory = "Advisory"
+)
+
+// Struct for an item found by the upgrade readiness check
+type readinessItem struct {
+	object  string
+	impact  string
+	details string
+}
+
+// Struct for an area verified by the upgrade readiness check
+type readinessArea struct {
+	title   string
+	okMsg   string
+	collect func(ctx context.Context, clients *Clients, target channel) ([]readinessItem, error)
+}
+
+// UpgradeReadiness checks if the cluster is ready to be upgraded to the target minor release
+func UpgradeReadiness(ctx context.Context, clients *Clients, opts Options) (*Result, error) {
+	result := &Result{Title: fmt.Sprintf("Checking upgrade readiness to %s...", opts.Upgrade.Target)}
+
+	major, minor, err := parseVersion(opts.Upgrade.Target)
+	if err != nil {
+		return result, err
+	}
+	target := channel{Major: major, Minor: minor}
+
+	areas := []readinessArea{
+		{"Checking target release availability...", "Target release is reachable from the current channel", targetReachability},
+		{"Checking clusteroperators upgradeable condition...", "All clusteroperators are upgradeable", upgradeableOperators},
+		{"Checking machineconfigpools...", "There is no paused or degraded machineconfigpool", pausedOrDegradedPools},
+		{"Checking restrictive PDBs...", "There is no restrictive PDB", restrictivePDBs},
+		{"Checking APIs removed in the target release...", "There is no API removed in the target release still in use", removedAPIs},
+		{"Checking OLM operators...", "All OLM operators are in Succeeded phase", failedOperators},
+		{"Checking feature gates...", "There is no feature set that prevents upgrades", noUpgradeFeatureSet},
+	}
+
+	blocking := 0
+	advisory := 0
+	failed := 0
+	for _, area := range areas {
+		items, collectErr := area.collect(ctx, clients, target)
+		if ctx.Err() != nil {
+			return result, ctx.Err()
+		}
+		// A failed area is reported and the remaining ones are still verified
+		if collectErr != nil {
+			failed++
+			result.add(Section{Title: area.title, Status: StatusError, Message: fmt.Sprintf("Unable to verify this area: %s", collectErr)})
+			continue
+		}
+
+		// Create a new table for the output
+		table := newTable("OBJECT", "IMPACT", "DETAILS")
+
+		areaBlocking := false
+		for _, item := range items {
+			if item.impact == impactBlocking {
+				areaBlocking = true
+				blocking++
+			} else {
+				advisory++
+			}
+			table.addRow(item.object, item.impact, item.details)
+		}
+
+		// Set output
+		section := Section{Title: area.title, Status: warningStatus(areaBlocking), Message: area.okMsg}
+		if len(items) > 0 {
+			section.Message = fmt.Sprintf("Found %d item(s) that may affect the upgrade", len(items))
+			section.Table = table
+		}
+		result.add(section)
+	}
+
+	// Set verdict
+	switch {
+	case blocking > 0:
+		result.add(Section{Status: StatusWarning, Message: fmt.Sprintf("NO-GO: %d blocking and %d advisory item(s) found", blocking, advisory)})
+	case failed > 0:
+		result.add(Section{Status: StatusWarning, Message: fmt.Sprintf("INCOMPLETE: no blocking item found, but %d area(s) could not be verified and %d advisory item(s) to review", failed, advisory)})
+	default:
+		result.add(Section{Status: StatusInfo, Message: fmt.Sprintf("GO: no blocking item found, %d advisory item(s) to review", advisory)})
+	}
+
+	return result, nil
+}
+
+// Check if the target release can be reached from the current version and channel
+func targetReachability(ctx context.Context, clients *Clients, target channel) ([]readinessItem, error) {
+	clusterversion, err := clients.Config.ConfigV1().ClusterVersions().Get(ctx, "version", metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	major, minor, err := parseVersion(clusterversion.Status.Desired.Version)
+	if err != nil {
+		return nil, err
+	}
+	current, err := parseChannel(clusterversion.Spec.Channel)
+	if err != nil {
+		current = channel{Prefix: "stable", Major: major, Minor: minor}
+	}
+	object := "clusterversion/version"
+	targetMinor := fmt.Sprintf("%d.%d", target.Major, target.Minor)
+	zStream := target.Major == major && target.Minor == minor
+
+	// Minor releases can only be skipped on EUS to EUS upgrades, same minor targets are z-stream updates
+	switch {
+	case target.Major != major || target.Minor < minor:
+		return []readinessItem{{object, impactBlocking, fmt.Sprintf("Cluster is running %s, upgrades to %s are not supported", clusterversion.Status.Desired.Version, targetMinor)}}, nil
+	case target.Minor == minor+2 && current.Prefix == "eus" && minor%2 == 0:
+		return []readinessItem{{object, impactAdvisory, fmt.Sprintf("EUS to EUS upgrade, the cluster goes through %d.%d and worker pools can be paused", major, minor+1)}}, nil
+	case target.Minor > minor+1:
+		return []readinessItem{{object, impactBlocking, fmt.Sprintf("Cluster is running %s, minor releases must be upgraded one at a time", clusterversion.Status.Desired.Version)}}, nil
+	}
+
+	// Look for the target in the recommended and conditional updates
+	for _, update := range clusterversion.Status.AvailableUpdates {
+		if strings.HasPrefix(update.Version, targetMinor+".") {
+			return []readinessItem{}, nil
+		}
+	}
+	for _, update := range clusterversion.Status.ConditionalUpdates {
+		if strings.HasPrefix(update.Release.Version, targetMinor+".") {
+			risks := []string{}
+			for _, risk := range update.Risks {
+				risks = append(risks, risk.Name)
+			}
+			return []readinessItem{{object, impactAdvisory, fmt.Sprintf("%s is only a conditional update, risks: %s", update.Release.Version, strings.Join(risks, ","))}}, nil
+		}
+	}
+
+	// Z-stream updates stay in the current channel, there may just be no newer patch yet
+	if zStream {
+		return []readinessItem{{object, impactAdvisory, fmt.Sprintf("Cluster is running %s, there is no newer %s z-stream update available in channel %s", clusterversion.Status.Desired.Version, targetMinor, clusterversion.Spec.Channel)}}, nil
+	}
+
+	return []readinessItem{{object, impactBlocking, fmt.Sprintf("There is no %s update available in channel %s, change the channel to %s-%s", targetMinor, clusterversion.Spec.Channel, current.Prefix, targetMinor)}}, nil
+}
+
+// Check the Upgradeable condition of the clusteroperators
+func upgradeableOperators(ctx context.Context, clients *Clients, target channel) ([]readinessItem, error) {
+	clusteroperators, err := clients.Config.ConfigV1().ClusterOperators().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	items := []readinessItem{}
+	for _, co := range clusteroperators.Items {
+		for _, condition := range co.Status.Conditions {
+			if condition.Type == configv1.OperatorUpgradeable && condition.Status == configv1.ConditionFalse {
+				items = append(items, readinessItem{"clusteroperator/" + co.Name, impactBlocking, fmt.Sprintf("%s: %s", condition.Reason, truncate(condition.Message, 80))})
+			}
+		}
+	}
+
+	return items, nil
+}
+
+// Check for paused or degraded machineconfigpools
+func pausedOrDegradedPools(ctx context.Context, clients *Clients, target channel) ([]readinessItem, error) {
+	data, err := listMachineConfigPools(ctx, clients)
+	if err != nil {
+		return nil, err
+	}
+
+	items := []readinessItem{}
+	for _, mcp := range data.Items {
+		object := "machineconfigpool/" + mcp.Metadata.Name
+		for _, condition := range mcp.Status.Conditions {
+			if condition.Type == "Degraded" && condition.Status == affirmative {
+				items = append(items, readinessItem{object, impactBlocking, "Pool is degraded: " + truncate(condition.Message, 80)})
+			}
+		}
+
+		// Only worker pools can stay paused during an upgrade
+		if mcp.Spec != nil && mcp.Spec.Paused {
+			if mcp.Metadata.Name == "master" {
+				items = append(items, readinessItem{object, impactBlocking, "Control plane pool is paused"})
+			} else {
+				items = append(items, readinessItem{object, impactAdvisory, "Pool is paused, its nodes will not be updated until it is unpaused"})
+			}
+		}
+	}
+
+	return items, nil
+}
+
+// Check for PDBs that block node drains
+func restrictivePDBs(ctx context.Context, clients *Clients, target channel) ([]readinessItem, error) {
+	pdbs, err := clients.Kube.PolicyV1().PodDisruptionBudgets("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	items := []readinessItem{}
+	for _, pdb := range pdbs.Items {
+		if reason := restrictivePDB(pdb); reason != "" {
+			items = append(items, readinessItem{fmt.Sprintf("pdb/%s/%s", pdb.Namespace, pdb.Name), impactAdvisory, "Node drain will be blocked, " + reason})
+		}
+	}
+
+	return items, nil
+}
+
+// Check for APIs removed in the target release that are still in use
+func removedAPIs(ctx context.Context, clients *Clients, target channel) ([]readinessItem, error) {
+	inUse, err := deprecatedAPIsInUse(ctx, clients)
+	if err != nil {
+		return nil, err
+	}
+	kubeMajor, kubeMinor := kubeVersion(target.Major, target.Minor)
+
+	items := []readinessItem{}
+	for _, count := range inUse {
+		removed, removedErr := removedBy(count.Status.RemovedInRelease, kubeMajor, kubeMinor)
+		if removedErr != nil {
+			return nil, removedErr
+		}
+		if removed {
+			items = append(items, readinessItem{count.Name, impactBlocking, fmt.Sprintf("Removed in Kubernetes %s, %d request(s) in the last 24h", count.Status.RemovedInRelease, count.Status.RequestCount)})
+		}
+	}
+
+	return items, nil
+}
+
+// Check for OLM operators that are not in Succeeded phase
+func failedOperators(ctx context.Context, clients *Clients, target channel) ([]readinessItem, error) {
+	csvs, err := clients.Dynamic.Resource(csvResource).Namespace("").List(ctx, metav1.ListOptions{})
+	if apierrors.IsNotFound(err) {
+		return []readinessItem{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	items := []readinessItem{}
+	for _, csv := range csvs.Items {
+		// Copies of global operators are reported by the original CSV
+		if _, copied := csv.GetLabels()["olm.copiedFrom"]; copied {
+			continue
+		}
+		phase, _, _ := unstructured.NestedString(csv.Object, "status", "phase")
+		if phase != "Succeeded" {
+			reason, _, _ := unstructured.NestedString(csv.Object, "status", "reason")
+			items = append(items, readinessItem{fmt.Sprintf("csv/%s/%s", csv.GetNamespace(), csv.GetName()), impactAdvisory, fmt.Sprintf("Operator is in %s phase: %s", phase, reason)})
+		}
+	}
+
+	return items, nil
+}
+
+// Check for feature sets that prevent upgrades
+func noUpgradeFeatureSet(ctx context.Context, clients *Clients, target channel) ([]readinessItem, error) {
+	featuregate, err := clients.Config.ConfigV1().FeatureGates().Get(ctx, "cluster", metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return []readinessItem{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	featureSet := featuregate.Spec.FeatureSet
+	if featureSet == configv1.CustomNoUpgrade || featureSet == configv1.TechPreviewNoUpgrade {
+		return []readinessItem{{"featuregate/cluster", impactBlocking, fmt.Sprintf("Feature set %s prevents upgrades", featureSet)}}, nil
+	}
+
+	return []readinessItem{}, nil
+}
@@ -0,0 +1,19 @@
+/*
+Copyright © 2023 Givaldo Lins <gilins@redhat.com>
+*/
+package checks
+
+const (
+	affirmative = "True"
+	negative    = "False"
+)
+
+// Truncate long messages to keep tables readable
+func truncate(message string, size int) string {
+	// Count runes so multi-byte characters are never cut in half
+	runes := []rune(message)
+	if len(runes) <= size {
+		return message
+	}
+	return string(runes[:size]) + "..."
+}
@@ -0,0 +1,304 @@
+/*
+Copyright © 2023 Givaldo Lins <gilins@redhat.com>
+*/
+package checks
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	configv1 "github.com/openshift/api/config/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Default update graph used when none is configured
+const defaultGraphURL = "https://api.openshift.com/api/upgrades_info/v1/graph"
+
+// Lifecycle of the OpenShift minor releases, keyed by "major.minor"
+//
+//go:embed lifecycle.json
+var embeddedLifecycle []byte
+
+// Struct used for the update graph
+type versionResponse struct {
+	Nodes []struct {
+		Version string `json:"version"`
+	} `json:"nodes"`
+}
+
+// Struct for the lifecycle of a minor release
+type minorLifecycle struct {
+	GA             string `json:"ga"`
+	MaintenanceEnd string `json:"maintenanceEnd"`
+	EUSEnd         string `json:"eusEnd"`
+}
+
+// Struct for a parsed update channel, like stable-4.12 or eus-4.14
+type channel struct {
+	Prefix string
+	Major  int
+	Minor  int
+}
+
+// VersionStatus checks if cluster is EOL
+func VersionStatus(ctx context.Context, clients *Clients, opts Options) (*Result, error) {
+	result := &Result{Title: "Checking if cluster is EOL..."}
+
+	// Get cluster version object
+	clusterversion, err := clients.Config.ConfigV1().ClusterVersions().Get(ctx, "version", metav1.GetOptions{})
+	if err != nil {
+		return result, err
+	}
+
+	// The lifecycle applies to the running minor, the channel may already point to the next one
+	version := runningVersion(clusterversion)
+	major, minor, err := parseVersion(version)
+	if err != nil {
+		return result, err
+	}
+	running := channel{Prefix: "stable", Major: major, Minor: minor}
+
+	// Use the channel to walk the update graph, or the running minor when no channel is set
+	current, err := parseChannel(clusterversion.Spec.Channel)
+	if err != nil {
+		current = running
+	} else {
+		running.Prefix = current.Prefix
+	}
+	clusterID := string(clusterversion.Spec.ClusterID)
+
+	// Check the support lifecycle of the current minor
+	lifecycle, err := loadLifecycle(opts.Version.LifecycleFile)
+	if err != nil {
+		return result, err
+	}
+	section, err := lifecycleSection(clusterID, version, running, lifecycle, time.Now())
+	if err != nil {
+		return result, err
+	}
+	result.add(section)
+
+	// Determine the graph source, an offline file takes precedence over any URL
+	graphURL := opts.Version.GraphURL
+	if graphURL == "" && clusterversion.Spec.Upstream != "" {
+		graphURL = string(clusterversion.Spec.Upstream)
+	}
+	if graphURL == "" {
+		graphURL = defaultGraphURL
+	}
+	source := graphURL
+	var latest int
+	if opts.Version.GraphFile != "" {
+		source = opts.Version.GraphFile
+		latest, err = latestMinorFromFile(opts.Version.GraphFile, current)
+	} else {
+		latest, err = latestMinorFromURL(ctx, graphURL, current)
+	}
+
+	// Set output
+	section = Section{Title: "Checking latest minor release available..."}
+	switch {
+	case err != nil:
+		section.Status = StatusInfo
+		section.Message = fmt.Sprintf("Unable to read the update graph from %s, skipping: %s", source, err)
+	case latest-running.Minor >= 3:
+		section.Status = StatusWarning
+		section.Message = fmt.Sprintf("Cluster %s is running version %s, which is more than 2 versions behind the latest minor release available (%d.%d) and might be out of support or close to reach its EOL.", clusterID, version, current.Major, latest)
+	default:
+		section.Status = StatusInfo
+		section.Message = fmt.Sprintf("Cluster %s is running version %s, which is not more than 2 versions behind from latest minor release (%d.%d).", clusterID, version, current.Major, latest)
+	}
+	result.add(section)
+
+	return result, nil
+}
+
+// Check the end of support date of the running minor
+func lifecycleSection(clusterID string, version string, current channel, lifecycle map[string]minorLifecycle, now time.Time) (Section, error) {
+	section := Section{Title: "Checking support lifecycle..."}
+
+	minor := fmt.Sprintf("%d.%d", current.Major, current.Minor)
+	entry, ok := lifecycle[minor]
+	if !ok {
+		section.Status = StatusInfo
+		section.Message = fmt.Sprintf("There is no lifecycle information for %s, please check the OpenShift Lifecycle page", minor)
+		return section, nil
+	}
+
+	// EUS channels are supported for longer on the minors that have an EUS term
+	end := entry.MaintenanceEnd
+	if current.Prefix == "eus" && entry.EUSEnd != "" {
+		end = entry.EUSEnd
+	}
+	endDate, err := time.Parse("2006-01-02", end)
+	if err != nil {
+		return section, fmt.Errorf("invalid end of support date for %s: %w", minor, err)
+	}
+
+	if now.After(endDate) {
+		section.Status = StatusWarning
+		section.Message = fmt.Sprintf("Cluster %s is running version %s, which reached its end of support on %s.\n  Please double check the OpenShift Lifecycle page to confirm that.", clusterID, version, end)
+	} else {
+		section.Status = StatusInfo
+		section.Message = fmt.Sprintf("Cluster %s is running version %s, which is supported until %s.", clusterID, version, end)
+	}
+	return section, nil
+}
+
+// Get the version of the last completed update, or the desired one when none completed yet
+func runningVersion(clusterversion *configv1.ClusterVersion) string {
+	for _, entry := range clusterversion.Status.History {
+		if entry.State == configv1.CompletedUpdate {
+			return entry.Version
+		}
+	}
+	return clusterversion.Status.Desired.Version
+}
+
+// Load the embedded lifecycle table, entries in the file override the embedded ones
+func loadLifecycle(file string) (map[string]minorLifecycle, error) {
+	lifecycle := map[string]minorLifecycle{}
+	err := json.Unmarshal(embeddedLifecycle, &lifecycle)
+	if err != nil {
+		return nil, err
+	}
+	if file == "" {
+		return lifecycle, nil
+	}
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	override := map[string]minorLifecycle{}
+	err = json.Unmarshal(data, &override)
+	if err != nil {
+		return nil, fmt.Errorf("invalid lifecycle file %s: %w", file, err)
+	}
+	for minor, entry := range override {
+		lifecycle[minor] = entry
+	}
+
+	return lifecycle, nil
+}
+
+// Walk the channels of the update graph until a channel without releases is found
+func latestMinorFromURL(ctx context.Context, graphURL string, current channel) (int, error) {
+	// EUS channels only exist for some minors, so walk the stable ones
+	prefix := current.Prefix
+	if prefix == "eus" {
+		prefix = "stable"
+	}
+
+	// Do not hang on disconnected clusters
+	client := &http.Client{Timeout: 10 * time.Second}
+
+	latest := current.Minor
+	for minor := current.Minor + 1; minor <= current.Minor+10; minor++ {
+		query := url.Values{"channel": []string{fmt.Sprintf("%s-%d.%d", prefix, current.Major, minor)}}
+		req, err := http.NewRequestWithContext(ctx, "GET", graphURL+"?"+query.Encode(), nil)
+		if err != nil {
+			return 0, err
+		}
+		req.Header.Add("Accept", "application/json")
+		resp, err := client.Do(req)
+		if err != nil {
+			return 0, err
+		}
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return 0, err
+		}
+		if resp.StatusCode != http.StatusOK {
+			return 0, fmt.Errorf("request failed with status %s", resp.Status)
+		}
+
+		var graph versionResponse
+		err = json.Unmarshal(body, &graph)
+		if err != nil {
+			return 0, err
+		}
+		if len(graph.Nodes) == 0 {
+			break
+		}
+		latest = maxMinor(graph, current.Major, latest)
+	}
+
+	return latest, nil
+}
+
+// Read the latest minor from an update graph saved to a file
+func latestMinorFromFile(file string, current channel) (int, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return 0, err
+	}
+	var graph versionResponse
+	err = json.Unmarshal(data, &graph)
+	if err != nil {
+		return 0, err
+	}
+
+	return maxMinor(graph, current.Major, current.Minor), nil
+}
+
+// Get the highest minor of a major release in the graph
+func maxMinor(graph versionResponse, major int, latest int) int {
+	for _, node := range graph.Nodes {
+		nodeMajor, nodeMinor, err := parseVersion(node.Version)
+		if err != nil || nodeMajor != major {
+			continue
+		}
+		if nodeMinor > latest {
+			latest = nodeMinor
+		}
+	}
+	return latest
+}
+
+// Parse an update channel like stable-4.12, fast-4.13, candidate-4.14 or eus-4.14
+func parseChannel(name string) (channel, error) {
+	prefix, version, found := strings.Cut(name, "-")
+	if !found {
+		return channel{}, fmt.Errorf("invalid channel %q", name)
+	}
+	switch prefix {
+	case "stable", "fast", "candidate", "eus":
+	default:
+		return channel{}, fmt.Errorf("unknown channel %q", name)
+	}
+	major, minor, err := parseVersion(version)
+	if err != nil {
+		return channel{}, fmt.Errorf("invalid channel %q: %w", name, err)
+	}
+
+	return channel{Prefix: prefix, Major: major, Minor: minor}, nil
+}
+
+// Parse the major and minor of a version like 4.12 or 4.12.15
+func parseVersion(version string) (int, int, error) {
+	parts := strings.SplitN(version, ".", 3)
+	if len(parts) < 2 {
+		return 0, 0, fmt.Errorf("invalid version %q", version)
+	}
+	major, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid version %q", version)
+	}
+	minor, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid version %q", version)
+	}
+
+	return major, minor, nil
+}
@@ -0,0 +1,76 @@
+/*
+Copyright © 2023 Givaldo Lins <gilins@redhat.com>
+*/
+package checks
+
+import "testing"
+
+func TestParseChannel(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    channel
+		wantErr bool
+	}{
+		{name: "stable-4.10", want: channel{Prefix: "stable", Major: 4, Minor: 10}},
+		{name: "eus-4.12", want: channel{Prefix: "eus", Major: 4, Minor: 12}},
+		{name: "fast-4.13", want: channel{Prefix: "fast", Major: 4, Minor: 13}},
+		{name: "candidate-4.9", want: channel{Prefix: "candidate", Major: 4, Minor: 9}},
+		{name: "", wantErr: true},
+		{name: "stable", wantErr: true},
+		{name: "stable-4", wantErr: true},
+		{name: "stable-four.ten", wantErr: true},
+		{name: "nightly-4.14", wantErr: true},
+	}
+
+	for _, test := range tests {
+		got, err := parseChannel(test.name)
+		if test.wantErr {
+			if err == nil {
+				t.Errorf("parseChannel(%q) = %+v, expected an error", test.name, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("parseChannel(%q) returned an error: %s", test.name, err)
+			continue
+		}
+		if got != test.want {
+			t.Errorf("parseChannel(%q) = %+v, expected %+v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestParseVersion(t *testing.T) {
+	tests := []struct {
+		version   string
+		wantMajor int
+		wantMinor int
+		wantErr   bool
+	}{
+		{version: "4.10", wantMajor: 4, wantMinor: 10},
+		{version: "4.10.3", wantMajor: 4, wantMinor: 10},
+		{version: "4.9.59", wantMajor: 4, wantMinor: 9},
+		{version: "4.14.0-rc.1", wantMajor: 4, wantMinor: 14},
+		{version: "", wantErr: true},
+		{version: "4", wantErr: true},
+		{version: "v4.12", wantErr: true},
+		{version: "4.x", wantErr: true},
+	}
+
+	for _, test := range tests {
+		major, minor, err := parseVersion(test.version)
+		if test.wantErr {
+			if err == nil {
+				t.Errorf("parseVersion(%q) = %d.%d, expected an error", test.version, major, minor)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("parseVersion(%q) returned an error: %s", test.version, err)
+			continue
+		}
+		if major != test.wantMajor || minor != test.wantMinor {
+			t.Errorf("parseVersion(%q) = %d.%d, expected %d.%d", test.version, major, minor, test.wantMajor, test.wantMinor)
+		}
+	}
+}
@@ -0,0 +1,167 @@
+/*
+Copyright © 2023 Givaldo Lins <gilins@redhat.com>
+*/
+package checks
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	admissionv1 "k8s.io/api/admissionregistration/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Struct for a validating or mutating webhook
+type webhook struct {
+	kind              string
+	configuration     string
+	name              string
+	clientConfig      admissionv1.WebhookClientConfig
+	failurePolicy     admissionv1.FailurePolicyType
+	namespaceSelector *metav1.LabelSelector
+	objectSelector    *metav1.LabelSelector
+}
+
+// WebhookStatus checks the admission webhooks backends and CA bundles
+func WebhookStatus(ctx context.Context, clients *Clients, opts Options) (*Result, error) {
+	result := &Result{Title: "Checking admission webhooks..."}
+
+	webhooks, err := listWebhooks(ctx, clients)
+	if err != nil {
+		return result, err
+	}
+
+	section, err := webhookBackends(ctx, clients, webhooks)
+	if err != nil {
+		return result, err
+	}
+	result.add(section)
+
+	result.add(webhookCABundles(webhooks, newCertWindows(opts.Certificates, time.Now())))
+
+	return result, nil
+}
+
+// List the validating and mutating webhooks
+func listWebhooks(ctx context.Context, clients *Clients) ([]webhook, error) {
+	webhooks := []webhook{}
+
+	validating, err := clients.Kube.AdmissionregistrationV1().ValidatingWebhookConfigurations().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for _, configuration := range validating.Items {
+		for _, hook := range configuration.Webhooks {
+			webhooks = append(webhooks, webhook{"validating", configuration.Name, hook.Name, hook.ClientConfig, failurePolicy(hook.FailurePolicy), hook.NamespaceSelector, hook.ObjectSelector})
+		}
+	}
+
+	mutating, err := clients.Kube.AdmissionregistrationV1().MutatingWebhookConfigurations().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for _, configuration := range mutating.Items {
+		for _, hook := range configuration.Webhooks {
+			webhooks = append(webhooks, webhook{"mutating", configuration.Name, hook.Name, hook.ClientConfig, failurePolicy(hook.FailurePolicy), hook.NamespaceSelector, hook.ObjectSelector})
+		}
+	}
+
+	return webhooks, nil
+}
+
+// Check that the services behind the webhooks exist and have ready endpoints
+func webhookBackends(ctx context.Context, clients *Clients, webhooks []webhook) (Section, error) {
+	section := Section{Title: "Checking webhooks backends..."}
+
+	services, err := clients.Kube.CoreV1().Services("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return section, err
+	}
+	existing := map[string]bool{}
+	for _, service := range services.Items {
+		existing[service.Namespace+"/"+service.Name] = true
+	}
+	ready, err := readyEndpoints(ctx, clients)
+	if err != nil {
+		return section, err
+	}
+
+	// Create a new table for the output
+	table := newTable("CONFIGURATION", "WEBHOOK", "TYPE", "SERVICE", "FAILURE POLICY", "NAMESPACES", "OBJECTS", "REASON")
+
+	blocking := false
+	for _, hook := range webhooks {
+		// Webhooks called by URL are outside of the cluster
+		service := hook.clientConfig.Service
+		if service == nil {
+			continue
+		}
+		key := service.Namespace + "/" + service.Name
+
+		reason := ""
+		switch {
+		case !existing[key]:
+			reason = "Service not found"
+		case ready[key] == 0:
+			reason = "Service has no ready endpoints"
+		default:
+			continue
+		}
+
+		// Broken webhooks that fail closed reject every request in their scope
+		if hook.failurePolicy == admissionv1.Fail {
+			blocking = true
+		}
+		table.addRow(hook.configuration, hook.name, hook.kind, key, string(hook.failurePolicy), selectorScope(hook.namespaceSelector), selectorScope(hook.objectSelector), reason)
+	}
+
+	// Set output
+	section.Status = warningStatus(blocking)
+	switch {
+	case blocking:
+		section.Message = "There is one or more broken webhooks with failurePolicy Fail, matching requests are rejected"
+		section.Table = table
+	case len(table.Rows) > 0:
+		section.Message = "There is one or more broken webhooks with failurePolicy Ignore, matching requests skip them"
+		section.Table = table
+	default:
+		section.Message = fmt.Sprintf("All %d webhooks point to services with ready endpoints", len(webhooks))
+	}
+
+	return section, nil
+}
+
+// Check the certificates in the webhooks CA bundles
+func webhookCABundles(webhooks []webhook, windows certWindows) Section {
+	section := Section{Title: "Checking webhooks CA bundles..."}
+
+	// Create a new table for the output
+	table := newCertTable()
+
+	for _, hook := range webhooks {
+		addCertRows(table, fmt.Sprintf("%swebhookconfiguration/%s/%s", hook.kind, hook.configuration, hook.name), hook.clientConfig.CABundle, windows)
+	}
+
+	// Set output
+	setCertOutput(&section, table, fmt.Sprintf("All webhooks CA bundles are valid for more than %d days", windows.warningDays()))
+
+	return section
+}
+
+// Failure policy of a webhook, Fail is the default
+func failurePolicy(policy *admissionv1.FailurePolicyType) admissionv1.FailurePolicyType {
+	if policy == nil {
+		return admissionv1.Fail
+	}
+	return *policy
+}
+
+// Describe the scope of a selector
+func selectorScope(selector *metav1.LabelSelector) string {
+	scope := metav1.FormatLabelSelector(selector)
+	if scope == "<none>" || scope == "" {
+		return "All"
+	}
+	return truncate(scope, 40)
+}